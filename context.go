@@ -5,8 +5,11 @@ package proj
 import "C"
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"runtime"
+	"runtime/cgo"
 	"sync"
 	"unsafe"
 )
@@ -21,6 +24,12 @@ const (
 	LogLevelTell  LogLevel = C.PJ_LOG_TELL
 )
 
+// slogLevelTrace is the slog.Level used for PROJ trace messages.
+const slogLevelTrace = slog.LevelDebug - 4
+
+// A LogFunc receives log messages emitted by PROJ.
+type LogFunc func(level LogLevel, msg string)
+
 var defaultContext = &Context{}
 
 func init() {
@@ -29,8 +38,12 @@ func init() {
 
 // A Context is a context.
 type Context struct {
-	mutex     sync.Mutex
-	pjContext *C.PJ_CONTEXT
+	mutex            sync.Mutex
+	pjContext        *C.PJ_CONTEXT
+	logHandle        cgo.Handle
+	networkHandle    cgo.Handle
+	fileSystemHandle cgo.Handle
+	searchPaths      []string
 }
 
 // NewContext returns a new Context.
@@ -52,6 +65,18 @@ func (c *Context) Destroy() {
 		C.proj_context_destroy(c.pjContext)
 		c.pjContext = nil
 	}
+	if c.logHandle != 0 {
+		c.logHandle.Delete()
+		c.logHandle = 0
+	}
+	if c.networkHandle != 0 {
+		c.networkHandle.Delete()
+		c.networkHandle = 0
+	}
+	if c.fileSystemHandle != 0 {
+		c.fileSystemHandle.Delete()
+		c.fileSystemHandle = 0
+	}
 }
 
 // SetLogLevel sets the log level.
@@ -61,6 +86,53 @@ func (c *Context) SetLogLevel(logLevel LogLevel) {
 	C.proj_log_level(c.pjContext, C.PJ_LOG_LEVEL(logLevel))
 }
 
+// SetLogFunc routes all log messages that PROJ emits for c to f. PROJ only
+// emits messages up to the level set with SetLogLevel. f is called while c is
+// locked, so it must not call any methods on c or on PJs created from c. If f
+// is nil then log messages are discarded.
+func (c *Context) SetLogFunc(f LogFunc) {
+	c.Lock()
+	defer c.Unlock()
+
+	var logHandle cgo.Handle
+	if f != nil {
+		logHandle = cgo.NewHandle(f)
+	}
+	C.go_proj_set_log_func(c.pjContext, C.uintptr_t(logHandle))
+	if c.logHandle != 0 {
+		c.logHandle.Delete()
+	}
+	c.logHandle = logHandle
+}
+
+// SetLogger routes all log messages that PROJ emits for c to logger and sets
+// c's log level to the most verbose level that logger has enabled. To identify
+// c in the logs, pass a logger with an attribute, for example
+// logger.With("proj_context", "tiles"). If logger is nil then log messages are
+// discarded.
+func (c *Context) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		c.SetLogFunc(nil)
+		return
+	}
+
+	ctx := context.Background()
+	switch {
+	case logger.Enabled(ctx, slogLevelTrace):
+		c.SetLogLevel(LogLevelTrace)
+	case logger.Enabled(ctx, slog.LevelDebug):
+		c.SetLogLevel(LogLevelDebug)
+	case logger.Enabled(ctx, slog.LevelError):
+		c.SetLogLevel(LogLevelError)
+	default:
+		c.SetLogLevel(LogLevelNone)
+	}
+
+	c.SetLogFunc(func(level LogLevel, msg string) {
+		logger.Log(ctx, level.slogLevel(), msg)
+	})
+}
+
 // SetSearchPaths sets the paths PROJ should be exploring to find the PROJ Data files.
 func (c *Context) SetSearchPaths(paths []string) {
 	c.Lock()
@@ -75,6 +147,107 @@ func (c *Context) SetSearchPaths(paths []string) {
 		pathPtr = unsafe.Pointer(&cPaths[0])
 	}
 	C.proj_context_set_search_paths(c.pjContext, C.int(len(cPaths)), (**C.char)(pathPtr))
+	c.searchPaths = nil
+	if len(paths) > 0 {
+		c.searchPaths = append([]string{}, paths...)
+	}
+}
+
+// SetDatabasePath sets the path to the PROJ database and the paths of
+// auxiliary databases to attach to it. If path is empty then the default
+// database is used.
+func (c *Context) SetDatabasePath(path string, auxDBPaths []string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	var cPath *C.char
+	if path != "" {
+		cPath = C.CString(path)
+		defer C.free(unsafe.Pointer(cPath))
+	}
+
+	cAuxDBPaths, freeCAuxDBPaths := newCStringList(auxDBPaths)
+	defer freeCAuxDBPaths()
+
+	if C.proj_context_set_database_path(c.pjContext, cPath, cAuxDBPaths, nil) == 0 {
+		if err := c.checkError(); err != nil {
+			return err
+		}
+		return fmt.Errorf("%s: cannot open database", path)
+	}
+	return nil
+}
+
+// DatabasePath returns the path to the PROJ database.
+func (c *Context) DatabasePath() (string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	cPath := C.proj_context_get_database_path(c.pjContext)
+	if err := c.checkError(); err != nil {
+		return "", err
+	}
+	if cPath == nil {
+		return "", fmt.Errorf("no database")
+	}
+	return C.GoString(cPath), nil
+}
+
+// SetAutocloseDatabase sets whether the PROJ database should be closed after
+// each operation that uses it, rather than when c is destroyed. This is
+// useful when the database files may be modified or replaced.
+func (c *Context) SetAutocloseDatabase(autoclose bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	var cAutoclose C.int
+	if autoclose {
+		cAutoclose = 1
+	}
+	C.proj_context_set_autoclose_database(c.pjContext, cAutoclose)
+}
+
+// DatabaseMetadata returns the value of the metadata item key from the
+// database, for example "EPSG.VERSION", "EPSG.DATE" or "PROJ_DATA.VERSION".
+func (c *Context) DatabaseMetadata(key string) (string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	cValue := C.proj_context_get_database_metadata(c.pjContext, cKey)
+	if err := c.checkError(); err != nil {
+		return "", err
+	}
+	if cValue == nil {
+		return "", fmt.Errorf("%s: database metadata not found", key)
+	}
+	return C.GoString(cValue), nil
+}
+
+// UserWritableDirectory returns the user writable directory used by PROJ to
+// store downloaded resources, creating it if create is true.
+func (c *Context) UserWritableDirectory(create bool) (string, error) {
+	if !Capabilities().UserWritableDirectory {
+		return "", ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	var cCreate C.int
+	if create {
+		cCreate = 1
+	}
+	cDirectory := C.proj_context_get_user_writable_directory(c.pjContext, cCreate)
+	if err := c.checkError(); err != nil {
+		return "", err
+	}
+	if cDirectory == nil {
+		return "", fmt.Errorf("user writable directory not available")
+	}
+	return C.GoString(cDirectory), nil
 }
 
 func (c *Context) Lock() {
@@ -281,6 +454,18 @@ func SetLogLevel(logLevel LogLevel) {
 	defaultContext.SetLogLevel(logLevel)
 }
 
+// SetLogFunc routes all log messages that PROJ emits for the default context
+// to f.
+func SetLogFunc(f LogFunc) {
+	defaultContext.SetLogFunc(f)
+}
+
+// SetLogger routes all log messages that PROJ emits for the default context to
+// logger.
+func SetLogger(logger *slog.Logger) {
+	defaultContext.SetLogger(logger)
+}
+
 // New returns a PJ with the given definition.
 func New(definition string) (*PJ, error) {
 	return defaultContext.New(definition)
@@ -307,6 +492,35 @@ func CreateCompoundCrs(name string, horizontalPJ *PJ, verticalPJ *PJ) (*PJ, erro
 	return defaultContext.CreateCompoundCrs(name, horizontalPJ, verticalPJ)
 }
 
+// SetDatabasePath sets the path to the PROJ database and the paths of
+// auxiliary databases of the default context.
+func SetDatabasePath(path string, auxDBPaths []string) error {
+	return defaultContext.SetDatabasePath(path, auxDBPaths)
+}
+
+// DatabasePath returns the path to the PROJ database of the default context.
+func DatabasePath() (string, error) {
+	return defaultContext.DatabasePath()
+}
+
+// DatabaseMetadata returns the value of the metadata item key from the
+// database of the default context.
+func DatabaseMetadata(key string) (string, error) {
+	return defaultContext.DatabaseMetadata(key)
+}
+
+// DatabaseStructure returns the SQL statements that create the structure of
+// the PROJ database of the default context.
+func DatabaseStructure() ([]string, error) {
+	return defaultContext.DatabaseStructure()
+}
+
+// UserWritableDirectory returns the user writable directory of the default
+// context.
+func UserWritableDirectory(create bool) (string, error) {
+	return defaultContext.UserWritableDirectory(create)
+}
+
 func GetAuthoritiesFromDatabase() ([]string, error) {
 	return defaultContext.GetAuthoritiesFromDatabase()
 }
@@ -315,6 +529,23 @@ func GetAllCRSCodes() ([]string, error) {
 	return defaultContext.GetAllCRSCodes()
 }
 
+// newCStringList returns strs as a NULL-terminated list of C strings and a
+// function that frees it. If strs is empty then the list is nil.
+func newCStringList(strs []string) (**C.char, func()) {
+	if len(strs) == 0 {
+		return nil, func() {}
+	}
+	cStrs := make([]*C.char, len(strs)+1)
+	for i, str := range strs {
+		cStrs[i] = C.CString(str)
+	}
+	return &cStrs[0], func() {
+		for _, cStr := range cStrs {
+			C.free(unsafe.Pointer(cStr))
+		}
+	}
+}
+
 func nullTerminatedListToGoSlice(res **C.char) []string {
 	goStrings := make([]string, 0)
 	for {
@@ -333,3 +564,27 @@ func nullTerminatedListToGoSlice(res **C.char) []string {
 	}
 	return goStrings
 }
+
+// slogLevel returns the slog.Level corresponding to l.
+func (l LogLevel) slogLevel() slog.Level {
+	switch l {
+	case LogLevelError:
+		return slog.LevelError
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelTrace:
+		return slogLevelTrace
+	default:
+		return slog.LevelInfo
+	}
+}
+
+//export goProjLogFunc
+func goProjLogFunc(logHandle C.uintptr_t, level C.int, msg *C.char) {
+	if logHandle == 0 {
+		return
+	}
+	if f, ok := cgo.Handle(logHandle).Value().(LogFunc); ok {
+		f(LogLevel(level), C.GoString(msg))
+	}
+}
@@ -1,9 +1,13 @@
 package proj_test
 
 import (
+	"bytes"
 	_ "embed"
+	"log/slog"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
@@ -156,6 +160,98 @@ func TestContext_SetSearchPaths(t *testing.T) {
 	context.SetSearchPaths([]string{"/tmp/data", "/tmp/data2"})
 }
 
+func TestContext_SetLogFunc(t *testing.T) {
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	var levels []proj.LogLevel
+	var msgs []string
+	context.SetLogLevel(proj.LogLevelError)
+	context.SetLogFunc(func(level proj.LogLevel, msg string) {
+		levels = append(levels, level)
+		msgs = append(msgs, msg)
+	})
+
+	_, err := context.New("invalid")
+	assert.Error(t, err)
+	assert.NotZero(t, msgs)
+	for i := range msgs {
+		assert.Equal(t, proj.LogLevelError, levels[i])
+		assert.NotEqual(t, "", msgs[i])
+	}
+
+	msgs = nil
+	context.SetLogFunc(nil)
+	_, err = context.New("invalid")
+	assert.Error(t, err)
+	assert.Zero(t, msgs)
+}
+
+func TestContext_SetLogger(t *testing.T) {
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buffer, nil))
+	context.SetLogger(logger.With("proj_context", "test"))
+
+	_, err := context.New("invalid")
+	assert.Error(t, err)
+	assert.Contains(t, buffer.String(), "level=ERROR")
+	assert.Contains(t, buffer.String(), "proj_context=test")
+}
+
+func TestContext_SetDatabasePath(t *testing.T) {
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	databasePath, err := context.DatabasePath()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(databasePath, "proj.db"))
+
+	context.SetAutocloseDatabase(true)
+	assert.NoError(t, context.SetDatabasePath(databasePath, nil))
+	_, err = context.New("EPSG:2056")
+	assert.NoError(t, err)
+
+	assert.Error(t, context.SetDatabasePath(filepath.Join(t.TempDir(), "missing.db"), nil))
+}
+
+func TestContext_DatabaseMetadata(t *testing.T) {
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	epsgVersion, err := context.DatabaseMetadata("EPSG.VERSION")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(epsgVersion, "v"))
+
+	_, err = context.DatabaseMetadata("INVALID.KEY")
+	assert.Error(t, err)
+}
+
+func TestContext_UserWritableDirectory(t *testing.T) {
+	if proj.VersionMajor < 7 {
+		t.Skip("user writable directory not supported")
+	}
+
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	directory, err := context.UserWritableDirectory(false)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", directory)
+}
+
 func Test_GetAuthoritiesFromDatabase(t *testing.T) {
 	res, err := proj.GetAuthoritiesFromDatabase()
 	if err != nil {
@@ -0,0 +1,360 @@
+package proj
+
+// #include <stdlib.h>
+// #include "go-proj.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// An AxisOrder is the order of the latitude and longitude in a Coord.
+type AxisOrder int
+
+// Axis orders.
+const (
+	AxisOrderLonLat AxisOrder = iota // Longitude first, as in normalized CRSs.
+	AxisOrderLatLon                  // Latitude first, as in EPSG:4326.
+)
+
+// DMSOptions are options for formatting angles as degrees, minutes, and
+// seconds.
+type DMSOptions struct {
+	Precision    int  // Number of decimals of the seconds.
+	Positive     rune // Suffix of positive angles, e.g. 'N'. If zero, no suffix is added.
+	Negative     rune // Suffix of negative angles, e.g. 'S'. If zero, negative angles are prefixed with a minus sign.
+	DegreeDigits int  // Minimum number of digits of the degrees, padded with zeros.
+	ASCII        bool // Use 'd' instead of '°' as the degree symbol.
+	Compact      bool // Omit the symbols and pad the minutes and seconds to two digits, as in DDMMSS.ss.
+}
+
+// Options for formatting latitudes and longitudes with hemisphere letters and
+// seconds with one decimal, e.g. 47°22'28.0"N and 8°32'28.0"E.
+var (
+	LatitudeDMSOptions  = DMSOptions{Precision: 1, Positive: 'N', Negative: 'S'}
+	LongitudeDMSOptions = DMSOptions{Precision: 1, Positive: 'E', Negative: 'W'}
+)
+
+// A dmsAxis is the axis of an angle as indicated by its hemisphere letter.
+type dmsAxis int
+
+const (
+	dmsAxisNone dmsAxis = iota
+	dmsAxisLat
+	dmsAxisLon
+)
+
+// dmsMarkers are the symbols that follow degrees (0), minutes (1), and seconds
+// (2). Symbols that are prefixes of other symbols come last.
+var dmsMarkers = []struct {
+	symbol string
+	unit   int
+}{
+	{"''", 2},
+	{"°", 0},
+	{"º", 0},
+	{"d", 0},
+	{"′", 1},
+	{"’", 1},
+	{"'", 1},
+	{"m", 1},
+	{"″", 2},
+	{"\"", 2},
+	{"s", 2},
+}
+
+// ParseDMS parses s as an angle and returns it in degrees. s may be:
+//
+//   - degrees, minutes, and seconds, with or without symbols, e.g.
+//     47°22'28.0", 47d22'28", 47 22 28, or 47:22:28.0, where only the last
+//     component may have a fraction;
+//   - signed decimal degrees, e.g. -8.541111;
+//   - compact DDMMSS.ss or DDDMMSS.ss, e.g. 472228.0 or 0083228, which is
+//     assumed for numbers without symbols that have five or more integer
+//     digits.
+//
+// Any of these may have a leading sign, or a leading or trailing hemisphere
+// letter N, S, E, or W, where S and W are negative. DMSToRad parses PROJ's own
+// syntax.
+func ParseDMS(s string) (float64, error) {
+	value, _, err := parseDMS(s)
+	return value, err
+}
+
+// ParseDMSCoord parses s as a latitude and a longitude separated by a comma, a
+// semicolon, or whitespace, as accepted by ParseDMS, and returns them in a
+// Coord in order. Hemisphere letters determine which value is the latitude
+// and which is the longitude. Without hemisphere letters, the values are
+// assumed to be in order.
+func ParseDMSCoord(s string, order AxisOrder) (Coord, error) {
+	first, second, err := splitDMSPair(s)
+	if err != nil {
+		return Coord{}, err
+	}
+	firstValue, firstAxis, err := parseDMS(first)
+	if err != nil {
+		return Coord{}, err
+	}
+	secondValue, secondAxis, err := parseDMS(second)
+	if err != nil {
+		return Coord{}, err
+	}
+
+	switch {
+	case firstAxis == dmsAxisNone && secondAxis == dmsAxisNone:
+		if order == AxisOrderLatLon {
+			firstAxis, secondAxis = dmsAxisLat, dmsAxisLon
+		} else {
+			firstAxis, secondAxis = dmsAxisLon, dmsAxisLat
+		}
+	case firstAxis == dmsAxisNone:
+		firstAxis = dmsAxisLat + dmsAxisLon - secondAxis
+	case secondAxis == dmsAxisNone:
+		secondAxis = dmsAxisLat + dmsAxisLon - firstAxis
+	case firstAxis == secondAxis:
+		return Coord{}, fmt.Errorf("%s: two angles on the same axis", s)
+	}
+
+	lat, lon := firstValue, secondValue
+	if firstAxis == dmsAxisLon {
+		lat, lon = secondValue, firstValue
+	}
+	if order == AxisOrderLatLon {
+		return NewCoord(lat, lon, 0, 0), nil
+	}
+	return NewCoord(lon, lat, 0, 0), nil
+}
+
+// FormatDMS formats value, in degrees, as degrees, minutes, and seconds.
+func FormatDMS(value float64, options DMSOptions) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	// Round the seconds before splitting, so that rounding carries into the
+	// minutes and degrees. The sign is that of the rounded value, so that
+	// values that round to zero are not negative.
+	precision := max(options.Precision, 0)
+	scale := math.Pow10(precision)
+	totalSeconds := math.Round(math.Abs(value)*3600*scale) / scale
+	negative := value < 0 && totalSeconds > 0
+
+	var sb strings.Builder
+	if negative && options.Negative == 0 {
+		sb.WriteByte('-')
+	}
+	degrees := math.Floor(totalSeconds / 3600)
+	minutes := math.Floor((totalSeconds - 3600*degrees) / 60)
+	seconds := totalSeconds - 3600*degrees - 60*minutes
+
+	secondsWidth := 2
+	if precision > 0 {
+		secondsWidth += precision + 1
+	}
+	fmt.Fprintf(&sb, "%0*d", max(options.DegreeDigits, 1), int(degrees))
+	switch {
+	case options.Compact:
+		fmt.Fprintf(&sb, "%02d%0*.*f", int(minutes), secondsWidth, precision, seconds)
+	case options.ASCII:
+		fmt.Fprintf(&sb, "d%d'%.*f\"", int(minutes), precision, seconds)
+	default:
+		fmt.Fprintf(&sb, "°%d'%.*f\"", int(minutes), precision, seconds)
+	}
+
+	switch {
+	case negative && options.Negative != 0:
+		sb.WriteRune(options.Negative)
+	case !negative && options.Positive != 0:
+		sb.WriteRune(options.Positive)
+	}
+	return sb.String()
+}
+
+// FormatDMSCoord formats the latitude and longitude of coord, which are in
+// order and in degrees, as degrees, minutes, and seconds with hemisphere
+// letters and precision decimals of the seconds, latitude first, e.g.
+// 47°22'28.0"N 8°32'28.0"E.
+func FormatDMSCoord(coord Coord, order AxisOrder, precision int) string {
+	lat, lon := coord[1], coord[0]
+	if order == AxisOrderLatLon {
+		lat, lon = coord[0], coord[1]
+	}
+	latOptions, lonOptions := LatitudeDMSOptions, LongitudeDMSOptions
+	latOptions.Precision, lonOptions.Precision = precision, precision
+	return FormatDMS(lat, latOptions) + " " + FormatDMS(lon, lonOptions)
+}
+
+// DMSToRad parses s with PROJ's proj_dmstor and returns it in radians.
+func DMSToRad(s string) (float64, error) {
+	cString := C.CString(s)
+	defer C.free(unsafe.Pointer(cString))
+
+	var cRest *C.char
+	r := float64(C.proj_dmstor(cString, &cRest))
+	if cRest == cString || math.IsInf(r, 0) || strings.TrimSpace(C.GoString(cRest)) != "" {
+		return 0, fmt.Errorf("%s: invalid DMS value", s)
+	}
+	return r, nil
+}
+
+// RadToDMS formats r, in radians, with PROJ's proj_rtodms2, e.g. 47d22'28"N.
+// pos and neg are the suffixes of positive and negative angles. If they are
+// zero then negative angles are prefixed with a minus sign.
+func RadToDMS(r float64, pos, neg byte) string {
+	var buffer [64]C.char
+	C.proj_rtodms2(&buffer[0], C.size_t(len(buffer)), C.double(r), C.int(pos), C.int(neg))
+	return C.GoString(&buffer[0])
+}
+
+// parseDMS parses s as an angle in degrees and returns it with the axis
+// indicated by its hemisphere letter.
+func parseDMS(s string) (float64, dmsAxis, error) {
+	str := strings.TrimSpace(s)
+	sign, axis := 1.0, dmsAxisNone
+	signed := false
+	if str != "" {
+		switch str[0] {
+		case '+':
+			signed, str = true, str[1:]
+		case '-':
+			sign, signed, str = -1, true, str[1:]
+		}
+	}
+	if str != "" {
+		if hemisphereSign, hemisphereAxis, ok := dmsHemisphere(str[0]); ok {
+			sign *= hemisphereSign
+			axis = hemisphereAxis
+			str = strings.TrimSpace(str[1:])
+		} else if hemisphereSign, hemisphereAxis, ok := dmsHemisphere(str[len(str)-1]); ok {
+			sign *= hemisphereSign
+			axis = hemisphereAxis
+			str = strings.TrimSpace(str[:len(str)-1])
+		}
+	}
+	if signed && axis != dmsAxisNone {
+		return 0, dmsAxisNone, fmt.Errorf("%s: both a sign and a hemisphere", s)
+	}
+	if str == "" {
+		return 0, dmsAxisNone, fmt.Errorf("%s: empty DMS value", s)
+	}
+
+	var components [3]float64
+	unit, last := -1, -1
+	fraction := false
+	for str != "" {
+		n := dmsNumberLen(str)
+		if n == 0 {
+			return 0, dmsAxisNone, fmt.Errorf("%s: invalid DMS value", s)
+		}
+		if fraction {
+			return 0, dmsAxisNone, fmt.Errorf("%s: fraction before the last component", s)
+		}
+		number := str[:n]
+		fraction = strings.Contains(number, ".")
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, dmsAxisNone, fmt.Errorf("%s: %w", s, err)
+		}
+		str = strings.TrimLeft(str[n:], " ")
+
+		unit = last + 1
+		marked := false
+		for _, marker := range dmsMarkers {
+			if strings.HasPrefix(str, marker.symbol) {
+				unit, marked = marker.unit, true
+				str = str[len(marker.symbol):]
+				break
+			}
+		}
+		if !marked {
+			str = strings.TrimPrefix(str, ":")
+		}
+		str = strings.TrimLeft(str, " ")
+
+		if unit <= last || unit > 2 {
+			return 0, dmsAxisNone, fmt.Errorf("%s: invalid DMS value", s)
+		}
+		if last == -1 && !marked && str == "" {
+			// A single number without symbols is either decimal degrees or
+			// compact DDMMSS.ss.
+			if integerDigits := strings.IndexByte(number+".", '.'); integerDigits >= 5 {
+				components[0] = math.Floor(value / 10000)
+				components[1] = math.Floor(math.Mod(value, 10000) / 100)
+				components[2] = math.Mod(value, 100)
+				break
+			}
+		}
+		components[unit] = value
+		last = unit
+	}
+
+	if components[1] >= 60 || components[2] >= 60 {
+		return 0, dmsAxisNone, fmt.Errorf("%s: minutes or seconds out of range", s)
+	}
+	value := sign * (components[0] + components[1]/60 + components[2]/3600)
+	switch {
+	case axis == dmsAxisLat && math.Abs(value) > 90:
+		return 0, dmsAxisNone, fmt.Errorf("%s: latitude out of range", s)
+	case axis == dmsAxisLon && math.Abs(value) > 180:
+		return 0, dmsAxisNone, fmt.Errorf("%s: longitude out of range", s)
+	}
+	return value, axis, nil
+}
+
+// dmsHemisphere returns the sign and axis of the hemisphere letter c.
+func dmsHemisphere(c byte) (float64, dmsAxis, bool) {
+	switch c {
+	case 'N':
+		return 1, dmsAxisLat, true
+	case 'S':
+		return -1, dmsAxisLat, true
+	case 'E':
+		return 1, dmsAxisLon, true
+	case 'W':
+		return -1, dmsAxisLon, true
+	default:
+		return 0, dmsAxisNone, false
+	}
+}
+
+// dmsNumberLen returns the length of the unsigned decimal number at the start
+// of s.
+func dmsNumberLen(s string) int {
+	n, dot := 0, false
+	for n < len(s) && ('0' <= s[n] && s[n] <= '9' || s[n] == '.' && !dot) {
+		dot = dot || s[n] == '.'
+		n++
+	}
+	return n
+}
+
+// splitDMSPair splits s into two angles.
+func splitDMSPair(s string) (string, string, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, ",;"); i >= 0 {
+		return s[:i], s[i+1:], nil
+	}
+
+	var letters []int
+	for i := 0; i < len(s); i++ {
+		if _, _, ok := dmsHemisphere(s[i]); ok {
+			letters = append(letters, i)
+		}
+	}
+	switch {
+	case len(letters) == 2 && letters[0] == 0:
+		return s[:letters[1]], s[letters[1]:], nil
+	case len(letters) == 2:
+		return s[:letters[0]+1], s[letters[0]+1:], nil
+	case len(letters) == 0:
+		if fields := strings.Fields(s); len(fields) == 2 {
+			return fields[0], fields[1], nil
+		}
+	}
+	return "", "", errors.New(s + ": not a pair of DMS values")
+}
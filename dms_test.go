@@ -0,0 +1,115 @@
+package proj_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+func TestParseDMS(t *testing.T) {
+	for i, tc := range []struct {
+		s             string
+		expected      float64
+		expectedError bool
+	}{
+		{s: `47°22'28.0"N`, expected: 47 + 22./60 + 28./3600},
+		{s: `8°32'28.0"E`, expected: 8 + 32./60 + 28./3600},
+		{s: `47°22′28″S`, expected: -(47 + 22./60 + 28./3600)},
+		{s: `47d22'28"W`, expected: -(47 + 22./60 + 28./3600)},
+		{s: `N 47 22 28`, expected: 47 + 22./60 + 28./3600},
+		{s: `47:22:28.5`, expected: 47 + 22./60 + 28.5/3600},
+		{s: `47°22.5'`, expected: 47 + 22.5/60},
+		{s: `-8.541111`, expected: -8.541111},
+		{s: `+8.541111`, expected: 8.541111},
+		{s: `472228.0N`, expected: 47 + 22./60 + 28./3600},
+		{s: `0083228E`, expected: 8 + 32./60 + 28./3600},
+		{s: `1800000W`, expected: -180},
+		{s: ``, expectedError: true},
+		{s: `N`, expectedError: true},
+		{s: `-47S`, expectedError: true},
+		{s: `47°61'`, expectedError: true},
+		{s: `47.5°22'`, expectedError: true},
+		{s: `91N`, expectedError: true},
+		{s: `47°22'28"X`, expectedError: true},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := proj.ParseDMS(tc.s)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assertInDelta(t, tc.expected, actual, 1e-12)
+		})
+	}
+}
+
+func TestParseDMSCoord(t *testing.T) {
+	lat, lon := 47+22./60+28./3600, 8+32./60+28./3600
+
+	for i, tc := range []struct {
+		s        string
+		order    proj.AxisOrder
+		expected proj.Coord
+	}{
+		{s: `47°22'28.0"N 8°32'28.0"E`, order: proj.AxisOrderLonLat, expected: proj.NewCoord(lon, lat, 0, 0)},
+		{s: `47°22'28.0"N 8°32'28.0"E`, order: proj.AxisOrderLatLon, expected: proj.NewCoord(lat, lon, 0, 0)},
+		{s: `8°32'28.0"E 47°22'28.0"N`, order: proj.AxisOrderLatLon, expected: proj.NewCoord(lat, lon, 0, 0)},
+		{s: `N47 22 28 E8 32 28`, order: proj.AxisOrderLatLon, expected: proj.NewCoord(lat, lon, 0, 0)},
+		{s: `8.5, -47.5`, order: proj.AxisOrderLonLat, expected: proj.NewCoord(8.5, -47.5, 0, 0)},
+		{s: `47.5 8.5`, order: proj.AxisOrderLatLon, expected: proj.NewCoord(47.5, 8.5, 0, 0)},
+		{s: `8.5; 47.5N`, order: proj.AxisOrderLatLon, expected: proj.NewCoord(47.5, 8.5, 0, 0)},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := proj.ParseDMSCoord(tc.s, tc.order)
+			assert.NoError(t, err)
+			assertInDelta(t, tc.expected.X(), actual.X(), 1e-12)
+			assertInDelta(t, tc.expected.Y(), actual.Y(), 1e-12)
+		})
+	}
+
+	_, err := proj.ParseDMSCoord(`47N 8N`, proj.AxisOrderLatLon)
+	assert.Error(t, err)
+	_, err = proj.ParseDMSCoord(`47`, proj.AxisOrderLatLon)
+	assert.Error(t, err)
+}
+
+func TestFormatDMS(t *testing.T) {
+	lat, lon := 47+22./60+28./3600, 8+32./60+28./3600
+
+	assert.Equal(t, `47°22'28.0"N`, proj.FormatDMS(lat, proj.LatitudeDMSOptions))
+	assert.Equal(t, `47°22'28.0"S`, proj.FormatDMS(-lat, proj.LatitudeDMSOptions))
+	assert.Equal(t, `8°32'28.0"W`, proj.FormatDMS(-lon, proj.LongitudeDMSOptions))
+	assert.Equal(t, `-8°32'28"`, proj.FormatDMS(-lon, proj.DMSOptions{}))
+	assert.Equal(t, `8d32'28.00"`, proj.FormatDMS(lon, proj.DMSOptions{Precision: 2, ASCII: true}))
+	assert.Equal(t, `0083228.0E`, proj.FormatDMS(lon, proj.DMSOptions{Precision: 1, Positive: 'E', Negative: 'W', DegreeDigits: 3, Compact: true}))
+	assert.Equal(t, `48°0'0"`, proj.FormatDMS(47.99999, proj.DMSOptions{}))
+	assert.Equal(t, `0°0'0.0"`, proj.FormatDMS(-0.00001, proj.DMSOptions{Precision: 1}))
+	assert.Equal(t, `0°0'0.0"N`, proj.FormatDMS(-0.00001, proj.LatitudeDMSOptions))
+	assert.Equal(t, `0°0'0.1"S`, proj.FormatDMS(-0.00003, proj.LatitudeDMSOptions))
+	assert.Equal(t, `NaN`, proj.FormatDMS(math.NaN(), proj.DMSOptions{}))
+
+	coord := proj.NewCoord(lon, lat, 0, 0)
+	formatted := proj.FormatDMSCoord(coord, proj.AxisOrderLonLat, 1)
+	assert.Equal(t, `47°22'28.0"N 8°32'28.0"E`, formatted)
+	parsed, err := proj.ParseDMSCoord(formatted, proj.AxisOrderLonLat)
+	assert.NoError(t, err)
+	assertInDelta(t, coord.X(), parsed.X(), 1e-9)
+	assertInDelta(t, coord.Y(), parsed.Y(), 1e-9)
+}
+
+func TestDMSToRad(t *testing.T) {
+	r, err := proj.DMSToRad(`47d22'28"N`)
+	assert.NoError(t, err)
+	assertInDelta(t, (47+22./60+28./3600)*math.Pi/180, r, 1e-12)
+
+	_, err = proj.DMSToRad(`not an angle`)
+	assert.Error(t, err)
+
+	assert.Equal(t, `47d22'28"N`, proj.RadToDMS(r, 'N', 'S'))
+	assert.Equal(t, `-47d22'28"`, proj.RadToDMS(-r, 0, 0))
+}
@@ -0,0 +1,407 @@
+package proj
+
+// #include <stdlib.h>
+// #include "go-proj.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/cgo"
+	"slices"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// A GridDownload describes a grid that a transformation may use and whether
+// it could be downloaded.
+type GridDownload struct {
+	GridUsed
+	Path       string // Path of the grid in the download directory, empty if it was not downloaded.
+	Downloaded bool   // True if the grid was downloaded by this call, false if it was already present.
+	Err        error  // Reason why the grid could not be downloaded, if any.
+}
+
+// downloadChunkSize is the size of the ranges requested by DownloadGridsFor.
+const downloadChunkSize = 1 << 20
+
+// A DownloadProgressFunc receives the progress of the download of grid as a
+// fraction between 0 and 1.
+type DownloadProgressFunc func(grid string, fraction float64)
+
+// Missing returns whether the grid is neither available locally nor present in
+// the download directory.
+func (d *GridDownload) Missing() bool {
+	return !d.Available && d.Path == ""
+}
+
+// DownloadGridsFor downloads all grids that the candidate operations from
+// sourceCRS to targetCRS within the optional area may use into dir. progress
+// is optional.
+//
+// If dir is empty then PROJ downloads the grids that are not available
+// locally into c's user writable directory, using c's URL endpoint, network
+// callbacks, and CA bundle, even if network access is disabled for c.
+// Otherwise every grid that is not yet in dir is fetched from c's URL
+// endpoint with c's NetworkClient, or with an HTTPNetworkClient if c has none,
+// and written to dir, even if it is available locally, so that dir holds all
+// grids. Grids already present are not downloaded again and interrupted
+// downloads into dir are resumed, so a download can be completed by calling
+// DownloadGridsFor again.
+//
+// Grids are only considered available if they are local files, even if
+// network access is enabled for c and PROJ could read them from the network.
+//
+// The returned GridDownloads report every grid, including those that are
+// still missing. An error is only returned if the grids could not be listed.
+func (c *Context) DownloadGridsFor(sourceCRS, targetCRS string, area *Area, dir string, progress DownloadProgressFunc) ([]GridDownload, error) {
+	if !Capabilities().Network {
+		return nil, ErrUnsupported
+	}
+
+	grids, err := c.gridsFor(sourceCRS, targetCRS, area)
+	if err != nil {
+		return nil, err
+	}
+
+	downloadDir := dir
+	if dir == "" {
+		if downloadDir, err = c.UserWritableDirectory(true); err != nil {
+			return nil, err
+		}
+	} else if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	gridDownloads := make([]GridDownload, 0, len(grids))
+	for _, grid := range grids {
+		gridDownload := GridDownload{
+			GridUsed: grid,
+		}
+		switch {
+		case dir == "" && grid.Available:
+		case !grid.DirectDownload || grid.ShortName == "":
+			gridDownload.Err = fmt.Errorf("%s: grid cannot be downloaded directly", grid.ShortName)
+		default:
+			var downloaded bool
+			var err error
+			if dir == "" {
+				downloaded, err = c.downloadFile(grid.ShortName, progress)
+			} else {
+				downloaded, err = c.downloadFileTo(dir, grid.ShortName, progress)
+			}
+			if err != nil {
+				gridDownload.Err = fmt.Errorf("%s: %w", grid.ShortName, err)
+			} else {
+				gridDownload.Downloaded = downloaded
+				gridDownload.Path = filepath.Join(downloadDir, grid.ShortName)
+			}
+		}
+		gridDownloads = append(gridDownloads, gridDownload)
+	}
+	return gridDownloads, nil
+}
+
+// DownloadGridsFor downloads all grids that the candidate operations from
+// sourceCRS to targetCRS may use into dir using the default context.
+func DownloadGridsFor(sourceCRS, targetCRS string, area *Area, dir string, progress DownloadProgressFunc) ([]GridDownload, error) {
+	return defaultContext.DownloadGridsFor(sourceCRS, targetCRS, area, dir, progress)
+}
+
+// gridsFor returns the grids used by all candidate operations from sourceCRS
+// to targetCRS within area, whether they are available or not. Grids are only
+// available if they are local files.
+func (c *Context) gridsFor(sourceCRS, targetCRS string, area *Area) ([]GridUsed, error) {
+	sourcePJ, err := c.New(sourceCRS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source CRS: %w", err)
+	}
+	defer sourcePJ.Destroy()
+
+	targetPJ, err := c.New(targetCRS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create target CRS: %w", err)
+	}
+	defer targetPJ.Destroy()
+
+	operations, err := c.createOperations(sourcePJ, targetPJ, area)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create operations: %w", err)
+	}
+
+	var grids []GridUsed
+	seen := make(map[string]bool)
+	for _, operation := range operations {
+		operationGrids, err := operation.GridsUsed()
+		operation.Destroy()
+		if err != nil {
+			return nil, err
+		}
+		for _, grid := range operationGrids {
+			if seen[grid.ShortName] {
+				continue
+			}
+			seen[grid.ShortName] = true
+			// With network access enabled PROJ reports grids that it could
+			// read from the network as available.
+			grid.Available = grid.Available && isLocalPath(grid.FullName)
+			grids = append(grids, grid)
+		}
+	}
+	return grids, nil
+}
+
+// isLocalPath returns whether name is the path of a local file rather than
+// empty or a URL.
+func isLocalPath(name string) bool {
+	return name != "" && !strings.Contains(name, "://")
+}
+
+// createOperations returns all candidate operations from sourcePJ to targetPJ
+// within area, including those that use grids that are not available.
+func (c *Context) createOperations(sourcePJ, targetPJ *PJ, area *Area) ([]*PJ, error) {
+	operationList, err := c.createOperationList(sourcePJ, targetPJ, area)
+	if err != nil {
+		return nil, err
+	}
+	defer C.proj_list_destroy(operationList)
+
+	return readPjList(operationList, sourcePJ)
+}
+
+// checkGrids returns an error if every candidate operation from sourcePJ to
+// targetPJ within area that uses grids misses some of them. The error names
+// the missing grids and wraps fs.ErrNotExist. Operations without grids are not
+// checked.
+func (c *Context) checkGrids(sourcePJ, targetPJ *PJ, area *Area) error {
+	_, err := c.availableGrids(sourcePJ, targetPJ, area)
+	return err
+}
+
+// availableGrids returns the grids of the candidate operations from sourcePJ
+// to targetPJ within area whose grids are all available. Operations without
+// grids, such as ballpark operations, are skipped. If every operation that
+// uses grids misses some of them then the returned error names the missing
+// grids and wraps fs.ErrNotExist.
+func (c *Context) availableGrids(sourcePJ, targetPJ *PJ, area *Area) ([]GridUsed, error) {
+	operations, err := c.createOperations(sourcePJ, targetPJ, area)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, operation := range operations {
+			operation.Destroy()
+		}
+	}()
+
+	var availableGrids []GridUsed
+	var missingGrids []string
+	seen := make(map[string]bool)
+	for _, operation := range operations {
+		grids, err := operation.GridsUsed()
+		if err != nil {
+			return nil, err
+		}
+		var operationMissingGrids []string
+		for _, grid := range grids {
+			if !grid.Available {
+				operationMissingGrids = append(operationMissingGrids, grid.ShortName)
+			}
+		}
+		if len(operationMissingGrids) == 0 {
+			for _, grid := range grids {
+				if !seen[grid.ShortName] {
+					seen[grid.ShortName] = true
+					availableGrids = append(availableGrids, grid)
+				}
+			}
+			continue
+		}
+		for _, grid := range operationMissingGrids {
+			if !slices.Contains(missingGrids, grid) {
+				missingGrids = append(missingGrids, grid)
+			}
+		}
+	}
+
+	if len(availableGrids) > 0 || len(missingGrids) == 0 {
+		return availableGrids, nil
+	}
+	return nil, fmt.Errorf("%s: grid not available: %w", strings.Join(missingGrids, ", "), fs.ErrNotExist)
+}
+
+// createOperationList returns the list of operations for createOperations.
+func (c *Context) createOperationList(sourcePJ, targetPJ *PJ, area *Area) (*C.PJ_OBJ_LIST, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	factoryContext := C.proj_create_operation_factory_context(c.pjContext, nil)
+	if factoryContext == nil {
+		return nil, c.newError(int(C.proj_context_errno(c.pjContext)))
+	}
+	defer C.proj_operation_factory_context_destroy(factoryContext)
+
+	C.proj_operation_factory_context_set_grid_availability_use(c.pjContext, factoryContext, C.PROJ_GRID_AVAILABILITY_IGNORED)
+	C.proj_operation_factory_context_set_spatial_criterion(c.pjContext, factoryContext, C.PROJ_SPATIAL_CRITERION_PARTIAL_INTERSECTION)
+	if area != nil {
+		C.proj_operation_factory_context_set_area_of_interest(c.pjContext, factoryContext,
+			(C.double)(area.westLonDegree), (C.double)(area.southLatDegree), (C.double)(area.eastLonDegree), (C.double)(area.northLatDegree))
+	}
+
+	operationList := C.proj_create_operations(c.pjContext, sourcePJ.pj, targetPJ.pj, factoryContext)
+	if operationList == nil {
+		return nil, c.newError(int(C.proj_context_errno(c.pjContext)))
+	}
+	return operationList, nil
+}
+
+// downloadFile downloads the resource name from c's URL endpoint into c's user
+// writable directory with PROJ, unless it is already present. Network access
+// is enabled for the duration of the download. It returns whether the resource
+// was downloaded.
+func (c *Context) downloadFile(name string, progress DownloadProgressFunc) (bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	if C.proj_is_download_needed(c.pjContext, cName, 0) == 0 {
+		return false, nil
+	}
+
+	if C.proj_context_is_network_enabled(c.pjContext) == 0 {
+		if C.proj_context_set_enable_network(c.pjContext, 1) == 0 {
+			return false, errors.New("network access not available")
+		}
+		defer C.proj_context_set_enable_network(c.pjContext, 0)
+	}
+
+	var progressHandle cgo.Handle
+	if progress != nil {
+		progressHandle = cgo.NewHandle(func(fraction float64) {
+			progress(name, fraction)
+		})
+		defer progressHandle.Delete()
+	}
+
+	if C.go_proj_download_file(c.pjContext, cName, 0, C.uintptr_t(progressHandle)) == 0 {
+		if err := c.checkError(); err != nil {
+			return false, err
+		}
+		return false, errors.New("download failed")
+	}
+	return true, nil
+}
+
+// downloadFileTo downloads the resource name from c's URL endpoint into dir
+// with c's NetworkClient, or with an HTTPNetworkClient if c has none, unless
+// it is already present. The resource is written to a .part file that is
+// renamed when the download is complete, and a .part file left by an
+// interrupted download is resumed, or only renamed if it is already complete.
+// It returns whether the resource was downloaded.
+func (c *Context) downloadFileTo(dir, name string, progress DownloadProgressFunc) (bool, error) {
+	path := filepath.Join(dir, name)
+	switch _, err := os.Stat(path); {
+	case err == nil:
+		return false, nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return false, err
+	}
+
+	c.Lock()
+	var client NetworkClient
+	if c.networkHandle != 0 {
+		client, _ = c.networkHandle.Value().(NetworkClient)
+	}
+	c.Unlock()
+	if client == nil {
+		client = &HTTPNetworkClient{}
+	}
+	url := strings.TrimSuffix(c.URLEndpoint(), "/") + "/" + name
+
+	partPath := path + ".part"
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	// A .part file may already hold the whole resource if a previous call was
+	// interrupted before renaming it, and then a request for the range after
+	// its end fails. So the size of the resource is first read along with the
+	// last byte of the .part file.
+	buffer := make([]byte, downloadChunkSize)
+	offset, size := fileInfo.Size(), int64(-1)
+	if offset > 0 {
+		_, header, err := client.ReadRange(url, offset-1, buffer[:1])
+		if err != nil {
+			return false, err
+		}
+		if size, err = contentRangeSize(header); err != nil {
+			return false, err
+		}
+	}
+
+	for size < 0 || offset < size {
+		n, header, err := client.ReadRange(url, offset, buffer)
+		if err != nil {
+			return false, err
+		}
+		if _, err := file.Write(buffer[:n]); err != nil {
+			return false, err
+		}
+		offset += int64(n)
+
+		if size, err = contentRangeSize(header); err != nil {
+			return false, err
+		}
+		if progress != nil {
+			progress(name, float64(offset)/float64(size))
+		}
+		if n == 0 && offset < size {
+			return false, io.ErrUnexpectedEOF
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return false, err
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// contentRangeSize returns the size of the complete resource from the
+// Content-Range header of a range response, e.g. 10 for "bytes 2-5/10".
+func contentRangeSize(header http.Header) (int64, error) {
+	contentRange := header.Get("Content-Range")
+	_, sizeStr, ok := strings.Cut(contentRange, "/")
+	if !ok {
+		return 0, fmt.Errorf("%q: invalid Content-Range", contentRange)
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("%q: invalid Content-Range", contentRange)
+	}
+	return size, nil
+}
+
+//export goProjDownloadProgress
+func goProjDownloadProgress(progressHandle C.uintptr_t, fraction C.double) C.int {
+	if f, ok := cgo.Handle(progressHandle).Value().(func(float64)); ok {
+		f(float64(fraction))
+	}
+	return 1
+}
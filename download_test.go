@@ -0,0 +1,204 @@
+package proj_test
+
+import (
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+func TestContext_DownloadGridsFor(t *testing.T) {
+	if !proj.Capabilities().Network {
+		t.Skip("network access not supported")
+	}
+
+	defer runtime.GC()
+
+	server := newTestGridServer(t, map[string][]byte{
+		"us_noaa_conus.tif": []byte("NADCON conus grid stand-in"),
+		"us_noaa_FL.tif":    []byte("NADCON Florida grid stand-in"),
+	})
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+	assert.NoError(t, context.SetNetworkCallbacks(&proj.HTTPNetworkClient{}))
+	assert.NoError(t, context.SetURLEndpoint(server.URL))
+
+	dir := t.TempDir()
+	area := proj.NewArea(-82, 26, -80, 28)
+	progress := make(map[string]float64)
+	gridDownloads, err := context.DownloadGridsFor("EPSG:4267", "EPSG:4269", area, dir, func(grid string, fraction float64) {
+		progress[grid] = fraction
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, gridDownloads)
+
+	// Grids are downloaded into dir even if they are available locally.
+	downloaded := make(map[string]bool)
+	for _, gridDownload := range gridDownloads {
+		if _, ok := server.grids[gridDownload.ShortName]; !ok {
+			assert.Equal(t, !gridDownload.Available, gridDownload.Missing(), gridDownload.ShortName)
+			assert.Error(t, gridDownload.Err)
+			continue
+		}
+		assert.NoError(t, gridDownload.Err)
+		assert.True(t, gridDownload.Downloaded)
+		assert.False(t, gridDownload.Missing())
+		assert.Equal(t, filepath.Join(dir, gridDownload.ShortName), gridDownload.Path)
+		data, err := os.ReadFile(gridDownload.Path)
+		assert.NoError(t, err)
+		assert.Equal(t, server.grids[gridDownload.ShortName], data)
+		downloaded[gridDownload.ShortName] = true
+	}
+	for grid, fraction := range progress {
+		assert.True(t, downloaded[grid], grid)
+		assert.True(t, fraction >= 0 && fraction <= 1)
+	}
+
+	// Grids that are already present are not downloaded again.
+	gridDownloads, err = context.DownloadGridsFor("EPSG:4267", "EPSG:4269", area, dir, nil)
+	assert.NoError(t, err)
+	for _, gridDownload := range gridDownloads {
+		if downloaded[gridDownload.ShortName] {
+			assert.NoError(t, gridDownload.Err)
+			assert.False(t, gridDownload.Downloaded)
+			assert.False(t, gridDownload.Missing())
+		}
+	}
+}
+
+func TestContext_DownloadGridsFor_resume(t *testing.T) {
+	if !proj.Capabilities().Network {
+		t.Skip("network access not supported")
+	}
+
+	defer runtime.GC()
+
+	grid := []byte("NADCON Florida grid stand-in")
+	server := newTestGridServer(t, map[string][]byte{
+		"us_noaa_FL.tif": grid,
+	})
+
+	// Without network callbacks grids are fetched with an HTTPNetworkClient.
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+	assert.NoError(t, context.SetURLEndpoint(server.URL))
+
+	// A partial download left by an interrupted call.
+	dir := t.TempDir()
+	partPath := filepath.Join(dir, "us_noaa_FL.tif.part")
+	assert.NoError(t, os.WriteFile(partPath, grid[:6], 0o644))
+
+	gridDownloads, err := context.DownloadGridsFor("EPSG:4267", "EPSG:4269", proj.NewArea(-82, 26, -80, 28), dir, nil)
+	assert.NoError(t, err)
+	for _, gridDownload := range gridDownloads {
+		if gridDownload.ShortName != "us_noaa_FL.tif" {
+			continue
+		}
+		assert.NoError(t, gridDownload.Err)
+		assert.True(t, gridDownload.Downloaded)
+		data, err := os.ReadFile(gridDownload.Path)
+		assert.NoError(t, err)
+		assert.Equal(t, grid, data)
+		_, err = os.Stat(partPath)
+		assert.IsError(t, err, fs.ErrNotExist)
+		// The size of the grid is read along with the last byte of the .part
+		// file before the rest is requested.
+		var requests []*http.Request
+		for _, request := range server.Requests() {
+			if request.URL.Path == "/us_noaa_FL.tif" {
+				requests = append(requests, request)
+			}
+		}
+		assert.Equal(t, 2, len(requests))
+		assert.Equal(t, "bytes=5-5", requests[0].Header.Get("Range"))
+		assert.True(t, strings.HasPrefix(requests[1].Header.Get("Range"), "bytes=6-"), requests[1].Header.Get("Range"))
+	}
+}
+
+func TestContext_DownloadGridsFor_resumeComplete(t *testing.T) {
+	if !proj.Capabilities().Network {
+		t.Skip("network access not supported")
+	}
+
+	defer runtime.GC()
+
+	grid := []byte("NADCON Florida grid stand-in")
+	server := newTestGridServer(t, map[string][]byte{
+		"us_noaa_FL.tif": grid,
+	})
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+	assert.NoError(t, context.SetURLEndpoint(server.URL))
+
+	// A complete download left by a call that was interrupted before renaming
+	// the .part file.
+	dir := t.TempDir()
+	partPath := filepath.Join(dir, "us_noaa_FL.tif.part")
+	assert.NoError(t, os.WriteFile(partPath, grid, 0o644))
+
+	gridDownloads, err := context.DownloadGridsFor("EPSG:4267", "EPSG:4269", proj.NewArea(-82, 26, -80, 28), dir, nil)
+	assert.NoError(t, err)
+	found := false
+	for _, gridDownload := range gridDownloads {
+		if gridDownload.ShortName != "us_noaa_FL.tif" {
+			continue
+		}
+		found = true
+		assert.NoError(t, gridDownload.Err)
+		assert.True(t, gridDownload.Downloaded)
+		data, err := os.ReadFile(gridDownload.Path)
+		assert.NoError(t, err)
+		assert.Equal(t, grid, data)
+		_, err = os.Stat(partPath)
+		assert.IsError(t, err, fs.ErrNotExist)
+	}
+	assert.True(t, found)
+}
+
+func TestContext_DownloadGridsFor_networkEnabled(t *testing.T) {
+	if !proj.Capabilities().Network {
+		t.Skip("network access not supported")
+	}
+
+	defer runtime.GC()
+
+	grid := []byte("NADCON Florida grid stand-in")
+	server := newTestGridServer(t, map[string][]byte{
+		"us_noaa_FL.tif": grid,
+	})
+
+	// With network access enabled PROJ considers grids on the CDN available,
+	// but they are still downloaded into dir.
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+	assert.NoError(t, context.SetNetworkCallbacks(&proj.HTTPNetworkClient{}))
+	assert.NoError(t, context.SetURLEndpoint(server.URL))
+	assert.NoError(t, context.EnableNetwork(true))
+
+	dir := t.TempDir()
+	gridDownloads, err := context.DownloadGridsFor("EPSG:4267", "EPSG:4269", proj.NewArea(-82, 26, -80, 28), dir, nil)
+	assert.NoError(t, err)
+	found := false
+	for _, gridDownload := range gridDownloads {
+		if gridDownload.ShortName != "us_noaa_FL.tif" {
+			continue
+		}
+		found = true
+		assert.NoError(t, gridDownload.Err)
+		assert.True(t, gridDownload.Downloaded)
+		assert.Equal(t, filepath.Join(dir, "us_noaa_FL.tif"), gridDownload.Path)
+		data, err := os.ReadFile(gridDownload.Path)
+		assert.NoError(t, err)
+		assert.Equal(t, grid, data)
+	}
+	assert.True(t, found)
+}
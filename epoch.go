@@ -0,0 +1,144 @@
+package proj
+
+// #include "go-proj.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// NewCoordinateMetadata returns a new CoordinateMetadata that attaches the
+// coordinate epoch epoch, as a decimal year, to crs. A CoordinateMetadata can
+// be used as the source or target of NewCRSToCRSFromPJ to transform
+// coordinates of a dynamic CRS that were observed at epoch.
+func (c *Context) NewCoordinateMetadata(crs *PJ, epoch float64) (*PJ, error) {
+	if !Capabilities().CoordinateMetadata {
+		return nil, ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if crs.context != c {
+		crs.context.Lock()
+		defer crs.context.Unlock()
+	}
+
+	return c.newPJ(C.proj_coordinate_metadata_create(c.pjContext, crs.pj, (C.double)(epoch)))
+}
+
+// NewCRSToCRSAtEpochs returns a new PJ that transforms coordinates of
+// sourceCRS observed at sourceEpoch to targetCRS at targetEpoch. Epochs are
+// decimal years. A NaN epoch attaches no epoch to its CRS, for static CRSs
+// and for targets of time-dependent transformations that are evaluated at
+// sourceEpoch.
+//
+// Time-dependent transformations, such as ITRF2014 to ETRF2014, are evaluated
+// at the coordinate epoch, so the fourth component of transformed coords is
+// not used. Moving coordinates between different epochs requires a point
+// motion operation, so an error is returned if sourceEpoch and targetEpoch
+// are both set and differ. Such coordinates can be moved to targetEpoch with
+// NewPointMotion first.
+func (c *Context) NewCRSToCRSAtEpochs(sourceCRS *PJ, sourceEpoch float64, targetCRS *PJ, targetEpoch float64, area *Area) (*PJ, error) {
+	if !math.IsNaN(sourceEpoch) && !math.IsNaN(targetEpoch) && sourceEpoch != targetEpoch {
+		return nil, fmt.Errorf("%g, %g: different source and target epochs", sourceEpoch, targetEpoch)
+	}
+
+	source, err := c.newCoordinateMetadataOrCRS(sourceCRS, sourceEpoch)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.newCoordinateMetadataOrCRS(targetCRS, targetEpoch)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewCRSToCRSFromPJ(source, target, area, "")
+}
+
+// NewPointMotion returns a new PJ that moves coordinates of crs observed at
+// sourceEpoch to their position at targetEpoch, using the point motion
+// operation of crs, e.g. a velocity grid of a deformation model. If the grids
+// of all point motion operations are missing then the returned error wraps
+// fs.ErrNotExist and names the missing grids, which can be fetched with
+// DownloadGridsFor or by enabling network access.
+func (c *Context) NewPointMotion(crs *PJ, sourceEpoch, targetEpoch float64) (*PJ, error) {
+	if !Capabilities().PointMotion {
+		return nil, ErrUnsupported
+	}
+
+	hasPointMotionOperation, err := crs.HasPointMotionOperation()
+	if err != nil {
+		return nil, err
+	}
+	if !hasPointMotionOperation {
+		return nil, errors.New("CRS has no point motion operation")
+	}
+
+	source, err := c.NewCoordinateMetadata(crs, sourceEpoch)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.NewCoordinateMetadata(crs, targetEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	// proj_create_crs_to_crs_from_pj skips operations whose grids are missing
+	// and falls back to a ballpark operation that leaves coordinates unmoved,
+	// so missing grids are reported first and ballpark operations are not
+	// allowed.
+	if err := c.checkGrids(source, target, nil); err != nil {
+		return nil, err
+	}
+	return c.NewCRSToCRSFromPJ(source, target, nil, "ALLOW_BALLPARK=NO")
+}
+
+// CoordinateMetadataGetEpoch returns the coordinate epoch of a
+// CoordinateMetadata, as a decimal year, or NaN if it has none.
+func (pj *PJ) CoordinateMetadataGetEpoch() (float64, error) {
+	if !Capabilities().CoordinateMetadata {
+		return 0, ErrUnsupported
+	}
+
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	lastErrno := C.proj_errno_reset(pj.pj)
+	defer C.proj_errno_restore(pj.pj, lastErrno)
+
+	epoch := C.proj_coordinate_metadata_get_epoch(pj.context.pjContext, pj.pj)
+	if errno := int(C.proj_errno(pj.pj)); errno != 0 {
+		return 0, pj.context.newError(errno)
+	}
+
+	return float64(epoch), nil
+}
+
+// NewCoordinateMetadata returns a new CoordinateMetadata using the default
+// context.
+func NewCoordinateMetadata(crs *PJ, epoch float64) (*PJ, error) {
+	return defaultContext.NewCoordinateMetadata(crs, epoch)
+}
+
+// NewCRSToCRSAtEpochs returns a new PJ that transforms coordinates between
+// epochs using the default context.
+func NewCRSToCRSAtEpochs(sourceCRS *PJ, sourceEpoch float64, targetCRS *PJ, targetEpoch float64, area *Area) (*PJ, error) {
+	return defaultContext.NewCRSToCRSAtEpochs(sourceCRS, sourceEpoch, targetCRS, targetEpoch, area)
+}
+
+// NewPointMotion returns a new PJ that moves coordinates between epochs within
+// crs using the default context.
+func NewPointMotion(crs *PJ, sourceEpoch, targetEpoch float64) (*PJ, error) {
+	return defaultContext.NewPointMotion(crs, sourceEpoch, targetEpoch)
+}
+
+// newCoordinateMetadataOrCRS returns a new CoordinateMetadata of crs at
+// epoch, or crs if epoch is NaN.
+func (c *Context) newCoordinateMetadataOrCRS(crs *PJ, epoch float64) (*PJ, error) {
+	if math.IsNaN(epoch) {
+		return crs, nil
+	}
+	return c.NewCoordinateMetadata(crs, epoch)
+}
@@ -0,0 +1,139 @@
+package proj_test
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+// zurichITRF2014 is a point near Zürich in ITRF2014 geocentric coordinates.
+var zurichITRF2014 = proj.NewCoord(4277886.566, 639334.431, 4672347.459, 0)
+
+func TestNewCoordinateMetadata(t *testing.T) {
+	if !proj.Capabilities().CoordinateMetadata {
+		t.Skip("coordinate metadata not supported")
+	}
+
+	defer runtime.GC()
+
+	crs, err := proj.New("EPSG:7789")
+	assert.NoError(t, err)
+
+	coordinateMetadata, err := proj.NewCoordinateMetadata(crs, 2010.0)
+	assert.NoError(t, err)
+	epoch, err := coordinateMetadata.CoordinateMetadataGetEpoch()
+	assert.NoError(t, err)
+	assert.Equal(t, 2010.0, epoch)
+
+	epoch, err = crs.CoordinateMetadataGetEpoch()
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(epoch))
+}
+
+func TestNewCRSToCRSAtEpochs(t *testing.T) {
+	if !proj.Capabilities().CoordinateMetadata {
+		t.Skip("coordinate metadata not supported")
+	}
+
+	defer runtime.GC()
+
+	itrf2014, err := proj.New("EPSG:7789")
+	assert.NoError(t, err)
+	etrf2014, err := proj.New("EPSG:8401")
+	assert.NoError(t, err)
+
+	// ITRF2014 to ETRF2014 is a rotation with the rates of EUREF Technical
+	// Note 1 (EPSG:8366), in milliarcseconds per year since 1989.0.
+	etrf2014At := func(epoch float64) proj.Coord {
+		masToRad := math.Pi / (180 * 3600 * 1000)
+		rx := 0.085 * masToRad * (epoch - 1989.0)
+		ry := 0.531 * masToRad * (epoch - 1989.0)
+		rz := -0.770 * masToRad * (epoch - 1989.0)
+		x, y, z := zurichITRF2014.X(), zurichITRF2014.Y(), zurichITRF2014.Z()
+		return proj.NewCoord(x-rz*y+ry*z, rz*x+y-rx*z, -ry*x+rx*y+z, 0)
+	}
+
+	for _, epoch := range []float64{2000.0, 2010.0, 2024.5} {
+		pj, err := proj.NewCRSToCRSAtEpochs(itrf2014, epoch, etrf2014, math.NaN(), nil)
+		assert.NoError(t, err)
+
+		actual, err := pj.Forward(zurichITRF2014)
+		assert.NoError(t, err)
+		expected := etrf2014At(epoch)
+		assertInDelta(t, expected.X(), actual.X(), 1e-3)
+		assertInDelta(t, expected.Y(), actual.Y(), 1e-3)
+		assertInDelta(t, expected.Z(), actual.Z(), 1e-3)
+	}
+
+	// Coordinates cannot be moved between epochs.
+	_, err = proj.NewCRSToCRSAtEpochs(itrf2014, 2010.0, etrf2014, 2024.5, nil)
+	assert.Error(t, err)
+}
+
+func TestNewPointMotion(t *testing.T) {
+	if !proj.Capabilities().PointMotion {
+		t.Skip("point motion not supported")
+	}
+
+	defer runtime.GC()
+
+	// NAD83(CSRS)v7 geographic 3D, with a velocity grid from NRCan.
+	crs, err := proj.New("EPSG:8254")
+	assert.NoError(t, err)
+
+	pj, err := proj.NewPointMotion(crs, 2010.0, 2025.0)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skip(err)
+	}
+	assert.NoError(t, err)
+
+	coord := proj.NewCoord(60, -100, 0, 0)
+	moved, err := pj.Forward(coord)
+	assert.NoError(t, err)
+	assertInDelta(t, coord.X(), moved.X(), 1e-5)
+	assertInDelta(t, coord.Y(), moved.Y(), 1e-5)
+	assertInDelta(t, coord.Z(), moved.Z(), 0.5)
+	assert.NotEqual(t, coord, moved)
+
+	back, err := pj.Inverse(moved)
+	assert.NoError(t, err)
+	assertInDelta(t, coord.X(), back.X(), 1e-9)
+	assertInDelta(t, coord.Y(), back.Y(), 1e-9)
+	assertInDelta(t, coord.Z(), back.Z(), 1e-4)
+
+	// WGS 84 is a datum ensemble without point motion operation.
+	crs, err = proj.New("EPSG:4326")
+	assert.NoError(t, err)
+	_, err = proj.NewPointMotion(crs, 2010.0, 2025.0)
+	assert.Error(t, err)
+}
+
+func TestNewPointMotion_missingGrid(t *testing.T) {
+	if !proj.Capabilities().PointMotion {
+		t.Skip("point motion not supported")
+	}
+
+	defer runtime.GC()
+
+	databasePath, err := proj.DatabasePath()
+	assert.NoError(t, err)
+
+	// Hide grids that are in the search paths or were downloaded before.
+	t.Setenv("PROJ_USER_WRITABLE_DIRECTORY", t.TempDir())
+	context := proj.NewContext()
+	_ = context.EnableNetwork(false)
+	context.SetSearchPaths([]string{t.TempDir()})
+	assert.NoError(t, context.SetDatabasePath(databasePath, nil))
+
+	crs, err := context.New("EPSG:8254")
+	assert.NoError(t, err)
+
+	_, err = context.NewPointMotion(crs, 2010.0, 2025.0)
+	assert.IsError(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,7 @@
+package proj
+
+// TransBoundsDensified exports transBoundsDensified, the fallback of
+// TransBounds for PROJ versions without proj_trans_bounds, for tests.
+func (pj *PJ) TransBoundsDensified(direction Direction, bounds Bounds, densifyPoints int) (Bounds, error) {
+	return pj.transBoundsDensified(direction, bounds, densifyPoints)
+}
@@ -0,0 +1,267 @@
+package proj
+
+// #include "go-proj.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// Factors are the distortion factors of a projection at a point. Angles are in
+// radians.
+type Factors struct {
+	MeridionalScale       float64 // Scale along the meridian, h.
+	ParallelScale         float64 // Scale along the parallel, k.
+	ArealScale            float64 // Areal scale, s.
+	AngularDistortion     float64 // Maximum angular distortion, ω.
+	MeridianParallelAngle float64 // Angle between the projected meridian and parallel, θ′.
+	MeridianConvergence   float64 // Meridian convergence, γ, also known as grid declination.
+	TissotSemiMajor       float64 // Semi-major axis of the Tissot indicatrix, a.
+	TissotSemiMinor       float64 // Semi-minor axis of the Tissot indicatrix, b.
+	DxDlam                float64 // Partial derivative ∂x/∂λ.
+	DxDphi                float64 // Partial derivative ∂x/∂φ.
+	DyDlam                float64 // Partial derivative ∂y/∂λ.
+	DyDphi                float64 // Partial derivative ∂y/∂φ.
+}
+
+// A DistortionSample is the distortion of a projection at a sampled point.
+// Angles are in radians.
+type DistortionSample struct {
+	Lon                 float64 // Longitude of the point, in degrees.
+	Lat                 float64 // Latitude of the point, in degrees.
+	ScaleError          float64 // Scale error in the direction of greatest distortion, e.g. -0.0004 for a scale of 0.9996.
+	AngularDistortion   float64 // Maximum angular distortion.
+	MeridianConvergence float64 // Meridian convergence.
+}
+
+// DistortionStatistics are the statistics of a distortion over the samples of
+// a DistortionReport.
+type DistortionStatistics struct {
+	Min  float64
+	Max  float64
+	Mean float64
+}
+
+// DistortionTolerances are the largest acceptable absolute values of the
+// distortions in a DistortionSample. Zero tolerances are not checked.
+type DistortionTolerances struct {
+	ScaleError          float64
+	AngularDistortion   float64
+	MeridianConvergence float64
+}
+
+// A DistortionReport is the distortion of a projection over an area.
+type DistortionReport struct {
+	Bounds              Bounds               // Sampled area, in degrees.
+	Samples             []DistortionSample   // Points where the distortion could be computed.
+	Failed              int                  // Number of points where the distortion could not be computed.
+	ScaleError          DistortionStatistics // Statistics of the scale error.
+	AngularDistortion   DistortionStatistics // Statistics of the angular distortion.
+	MeridianConvergence DistortionStatistics // Statistics of the meridian convergence.
+}
+
+// AnalyzeDistortion samples the distortion factors of the projection pj on a
+// grid of samples by samples points over bounds, with XMin, YMin, XMax, and
+// YMax as the west longitude, south latitude, east longitude, and north
+// latitude in degrees. If bounds is the zero Bounds then the area of use of pj
+// is sampled. Bounds with XMin greater than XMax cross the antimeridian.
+func AnalyzeDistortion(pj *PJ, bounds Bounds, samples int) (*DistortionReport, error) {
+	if samples < 1 {
+		return nil, fmt.Errorf("%d: invalid number of samples", samples)
+	}
+	if bounds == (Bounds{}) {
+		areaOfUse := pj.GetAreaOfUse()
+		if areaOfUse == nil {
+			return nil, errors.New("no bounds and no area of use")
+		}
+		bounds = Bounds{
+			XMin: areaOfUse.WestLon,
+			YMin: areaOfUse.SouthLat,
+			XMax: areaOfUse.EastLon,
+			YMax: areaOfUse.NorthLat,
+		}
+	}
+
+	east := bounds.XMax
+	if east < bounds.XMin {
+		east += 360
+	}
+	lonLats := make([][2]float64, 0, samples*samples)
+	coords := make([]Coord, 0, samples*samples)
+	for i := 0; i < samples; i++ {
+		lat := samplePosition(bounds.YMin, bounds.YMax, i, samples)
+		for j := 0; j < samples; j++ {
+			lon := samplePosition(bounds.XMin, east, j, samples)
+			if lon > 180 {
+				lon -= 360
+			}
+			lonLats = append(lonLats, [2]float64{lon, lat})
+			coords = append(coords, NewCoord(lon*math.Pi/180, lat*math.Pi/180, 0, 0))
+		}
+	}
+
+	factors := make([]Factors, len(coords))
+	_ = pj.FactorsArray(coords, factors)
+
+	report := &DistortionReport{
+		Bounds: bounds,
+	}
+	for i, f := range factors {
+		if math.IsNaN(f.TissotSemiMajor) {
+			report.Failed++
+			continue
+		}
+		scaleError := f.TissotSemiMajor - 1
+		if math.Abs(f.TissotSemiMinor-1) > math.Abs(scaleError) {
+			scaleError = f.TissotSemiMinor - 1
+		}
+		report.Samples = append(report.Samples, DistortionSample{
+			Lon:                 lonLats[i][0],
+			Lat:                 lonLats[i][1],
+			ScaleError:          scaleError,
+			AngularDistortion:   f.AngularDistortion,
+			MeridianConvergence: f.MeridianConvergence,
+		})
+	}
+	if len(report.Samples) == 0 {
+		return nil, errors.New("no distortion factors could be computed")
+	}
+
+	report.ScaleError = newDistortionStatistics(report.Samples, func(s *DistortionSample) float64 {
+		return s.ScaleError
+	})
+	report.AngularDistortion = newDistortionStatistics(report.Samples, func(s *DistortionSample) float64 {
+		return s.AngularDistortion
+	})
+	report.MeridianConvergence = newDistortionStatistics(report.Samples, func(s *DistortionSample) float64 {
+		return s.MeridianConvergence
+	})
+	return report, nil
+}
+
+// Exceeding returns the samples of r where any distortion exceeds tolerances.
+func (r *DistortionReport) Exceeding(tolerances DistortionTolerances) []DistortionSample {
+	var exceeding []DistortionSample
+	for _, s := range r.Samples {
+		if exceedsTolerance(s.ScaleError, tolerances.ScaleError) ||
+			exceedsTolerance(s.AngularDistortion, tolerances.AngularDistortion) ||
+			exceedsTolerance(s.MeridianConvergence, tolerances.MeridianConvergence) {
+			exceeding = append(exceeding, s)
+		}
+	}
+	return exceeding
+}
+
+// Factors returns the distortion factors of the projection pj at the geodetic
+// coordinate coord, with longitude and latitude in radians.
+func (pj *PJ) Factors(coord Coord) (Factors, error) {
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	lastErrno := C.proj_errno_reset(pj.pj)
+	defer C.proj_errno_restore(pj.pj, lastErrno)
+
+	cFactors := C.proj_factors(pj.pj, *(*C.PJ_COORD)(unsafe.Pointer(&coord)))
+	if errno := int(C.proj_errno(pj.pj)); errno != 0 {
+		return Factors{}, pj.context.newError(errno)
+	}
+
+	return Factors{
+		MeridionalScale:       float64(cFactors.meridional_scale),
+		ParallelScale:         float64(cFactors.parallel_scale),
+		ArealScale:            float64(cFactors.areal_scale),
+		AngularDistortion:     float64(cFactors.angular_distortion),
+		MeridianParallelAngle: float64(cFactors.meridian_parallel_angle),
+		MeridianConvergence:   float64(cFactors.meridian_convergence),
+		TissotSemiMajor:       float64(cFactors.tissot_semimajor),
+		TissotSemiMinor:       float64(cFactors.tissot_semiminor),
+		DxDlam:                float64(cFactors.dx_dlam),
+		DxDphi:                float64(cFactors.dx_dphi),
+		DyDlam:                float64(cFactors.dy_dlam),
+		DyDphi:                float64(cFactors.dy_dphi),
+	}, nil
+}
+
+// FactorsArray computes the distortion factors of the projection pj at each of
+// coords into factors, in a single call to PROJ. factors must be at least as
+// long as coords. The factors of coords where the computation fails are set to
+// NaN and the error of the first such coord is returned.
+func (pj *PJ) FactorsArray(coords []Coord, factors []Factors) error {
+	if len(factors) < len(coords) {
+		return fmt.Errorf("%d factors for %d coords", len(factors), len(coords))
+	}
+	if len(coords) == 0 {
+		return nil
+	}
+
+	errnos := pj.factorsArray(coords, factors)
+
+	var err error
+	for i, errno := range errnos {
+		if errno == 0 {
+			continue
+		}
+		factors[i] = nanFactors()
+		if err == nil {
+			err = fmt.Errorf("coord %d: %w", i, pj.context.newError(int(errno)))
+		}
+	}
+	return err
+}
+
+// factorsArray computes the factors of coords and returns the errno of each
+// coord. Errors are wrapped by the caller, as formatting an Error locks the
+// context.
+func (pj *PJ) factorsArray(coords []Coord, factors []Factors) []C.int {
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	lastErrno := C.proj_errno_reset(pj.pj)
+	defer C.proj_errno_restore(pj.pj, lastErrno)
+
+	errnos := make([]C.int, len(coords))
+	C.go_proj_factors_array(pj.pj, (C.size_t)(len(coords)), (*C.PJ_COORD)(unsafe.Pointer(&coords[0])),
+		(*C.double)(unsafe.Pointer(&factors[0])), &errnos[0])
+	return errnos
+}
+
+// nanFactors returns Factors with all fields set to NaN.
+func nanFactors() Factors {
+	nan := math.NaN()
+	return Factors{nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan}
+}
+
+// samplePosition returns the position of sample i of n between min and max.
+// A single sample is placed in the middle.
+func samplePosition(min, max float64, i, n int) float64 {
+	if n == 1 {
+		return (min + max) / 2
+	}
+	return min + float64(i)*(max-min)/float64(n-1)
+}
+
+// newDistortionStatistics returns the statistics of value over samples.
+func newDistortionStatistics(samples []DistortionSample, value func(*DistortionSample) float64) DistortionStatistics {
+	statistics := DistortionStatistics{
+		Min: math.Inf(1),
+		Max: math.Inf(-1),
+	}
+	sum := 0.0
+	for i := range samples {
+		v := value(&samples[i])
+		statistics.Min = math.Min(statistics.Min, v)
+		statistics.Max = math.Max(statistics.Max, v)
+		sum += v
+	}
+	statistics.Mean = sum / float64(len(samples))
+	return statistics
+}
+
+// exceedsTolerance returns whether the absolute value of value exceeds a
+// non-zero tolerance.
+func exceedsTolerance(value, tolerance float64) bool {
+	return tolerance != 0 && math.Abs(value) > tolerance
+}
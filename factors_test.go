@@ -0,0 +1,98 @@
+package proj_test
+
+import (
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+func TestPJ_Factors(t *testing.T) {
+	defer runtime.GC()
+
+	pj, err := proj.New("+proj=utm +zone=32 +ellps=GRS80")
+	assert.NoError(t, err)
+
+	coord := proj.NewCoord(9, 0, 0, 0)
+	factors, err := pj.Factors(coord.DegToRad())
+	assert.NoError(t, err)
+	assertInDelta(t, 0.9996, factors.MeridionalScale, 1e-9)
+	assertInDelta(t, 0.9996, factors.ParallelScale, 1e-9)
+	assertInDelta(t, 0, factors.MeridianConvergence, 1e-9)
+	assertInDelta(t, 0, factors.AngularDistortion, 1e-9)
+	assertInDelta(t, math.Pi/2, factors.MeridianParallelAngle, 1e-9)
+
+	pj, err = proj.New("+proj=merc +R=6371000")
+	assert.NoError(t, err)
+
+	coord = proj.NewCoord(0, 60, 0, 0)
+	factors, err = pj.Factors(coord.DegToRad())
+	assert.NoError(t, err)
+	assertInDelta(t, 2, factors.MeridionalScale, 1e-9)
+	assertInDelta(t, 2, factors.ParallelScale, 1e-9)
+	assertInDelta(t, 4, factors.ArealScale, 1e-9)
+	assertInDelta(t, 2, factors.TissotSemiMajor, 1e-9)
+	assertInDelta(t, 2, factors.TissotSemiMinor, 1e-9)
+
+	_, err = pj.Factors(proj.NewCoord(0, 2, 0, 0))
+	assert.Error(t, err)
+}
+
+func TestPJ_FactorsArray(t *testing.T) {
+	defer runtime.GC()
+
+	pj, err := proj.New("+proj=merc +R=6371000")
+	assert.NoError(t, err)
+
+	coords := []proj.Coord{
+		proj.NewCoord(0, 0, 0, 0),
+		proj.NewCoord(0, math.Pi/3, 0, 0),
+		proj.NewCoord(0, 2, 0, 0),
+	}
+	factors := make([]proj.Factors, len(coords))
+	assert.Error(t, pj.FactorsArray(coords, factors))
+	assertInDelta(t, 1, factors[0].ParallelScale, 1e-9)
+	assertInDelta(t, 2, factors[1].ParallelScale, 1e-9)
+	assert.True(t, math.IsNaN(factors[2].ParallelScale))
+
+	assert.NoError(t, pj.FactorsArray(coords[:2], factors))
+	assert.Error(t, pj.FactorsArray(coords, factors[:1]))
+}
+
+func TestAnalyzeDistortion(t *testing.T) {
+	defer runtime.GC()
+
+	pj, err := proj.New("+proj=merc +R=6371000")
+	assert.NoError(t, err)
+
+	report, err := proj.AnalyzeDistortion(pj, proj.Bounds{XMin: -10, YMin: 0, XMax: 10, YMax: 60}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, len(report.Samples))
+	assert.Equal(t, 0, report.Failed)
+	assertInDelta(t, 0, report.ScaleError.Min, 1e-9)
+	assertInDelta(t, 1, report.ScaleError.Max, 1e-9)
+	assertInDelta(t, (2/math.Sqrt(3)-1+1)/3, report.ScaleError.Mean, 1e-9)
+	assertInDelta(t, 0, report.AngularDistortion.Max, 1e-9)
+	assertInDelta(t, 0, report.MeridianConvergence.Max, 1e-9)
+
+	exceeding := report.Exceeding(proj.DistortionTolerances{ScaleError: 0.5})
+	assert.Equal(t, 3, len(exceeding))
+	for _, sample := range exceeding {
+		assert.Equal(t, 60, sample.Lat)
+	}
+	assert.Equal(t, 0, len(report.Exceeding(proj.DistortionTolerances{})))
+
+	// Bounds crossing the antimeridian.
+	report, err = proj.AnalyzeDistortion(pj, proj.Bounds{XMin: 170, YMin: 0, XMax: -170, YMax: 0}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 180, report.Samples[1].Lon)
+
+	_, err = proj.AnalyzeDistortion(pj, proj.Bounds{}, 3)
+	assert.Error(t, err)
+
+	_, err = proj.AnalyzeDistortion(pj, proj.Bounds{XMin: -10, YMin: 0, XMax: 10, YMax: 60}, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,289 @@
+package proj
+
+// #include <stdlib.h>
+// #include "go-proj.h"
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"runtime/cgo"
+	"strings"
+	"unsafe"
+)
+
+// fileSystemRoot is the search path under which PROJ finds the files of a
+// file system set with SetFileSystem.
+const fileSystemRoot = "/go-proj-fs"
+
+// Access modes of PROJ_OPEN_ACCESS.
+const (
+	fileAccessReadOnly   = 0
+	fileAccessReadUpdate = 1
+	fileAccessCreate     = 2
+)
+
+// A fileSystem routes PROJ's file access to an fs.FS, and to the operating
+// system for files outside fileSystemRoot.
+type fileSystem struct {
+	fsys fs.FS
+}
+
+// A fileSystemFile is a file opened by PROJ.
+type fileSystemFile interface {
+	io.ReadSeekCloser
+}
+
+// A readSeekNopCloser is an in-memory fileSystemFile.
+type readSeekNopCloser struct {
+	*bytes.Reader
+}
+
+// SetFileSystem makes PROJ read its resource files, such as grids and init
+// files, from fsys instead of from the search paths. fsys replaces any search
+// paths previously set with SetSearchPaths.
+//
+// The PROJ database is opened directly by SQLite and cannot be read from
+// fsys. The database that c currently uses is kept, but auxiliary databases
+// must be set again with SetDatabasePath after calling SetFileSystem. Files
+// that PROJ writes, such as downloaded grids, are written to the operating
+// system's file system.
+func (c *Context) SetFileSystem(fsys fs.FS) error {
+	if !Capabilities().FileAPI {
+		return ErrUnsupported
+	}
+	if fsys == nil {
+		return fmt.Errorf("nil file system")
+	}
+
+	databasePath, err := c.DatabasePath()
+	if err != nil {
+		databasePath = ""
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	fileSystemHandle := cgo.NewHandle(&fileSystem{fsys: fsys})
+	if C.go_proj_set_fileapi(c.pjContext, C.uintptr_t(fileSystemHandle)) == 0 {
+		fileSystemHandle.Delete()
+		return fmt.Errorf("cannot set file API")
+	}
+	if c.fileSystemHandle != 0 {
+		c.fileSystemHandle.Delete()
+	}
+	c.fileSystemHandle = fileSystemHandle
+
+	cFileSystemRoot := C.CString(fileSystemRoot)
+	defer C.free(unsafe.Pointer(cFileSystemRoot))
+	C.proj_context_set_search_paths(c.pjContext, 1, &cFileSystemRoot)
+	c.searchPaths = []string{}
+
+	if databasePath != "" {
+		cDatabasePath := C.CString(databasePath)
+		defer C.free(unsafe.Pointer(cDatabasePath))
+		if C.proj_context_set_database_path(c.pjContext, cDatabasePath, nil, nil) == 0 {
+			return fmt.Errorf("%s: cannot open database", databasePath)
+		}
+	}
+	return nil
+}
+
+// SetFileSystem makes PROJ read its resource files from fsys for the default
+// context.
+func SetFileSystem(fsys fs.FS) error {
+	return defaultContext.SetFileSystem(fsys)
+}
+
+// Close implements io.Closer.
+func (readSeekNopCloser) Close() error {
+	return nil
+}
+
+// fsName returns the name of filename in s.fsys and whether filename is in
+// fileSystemRoot.
+func (s *fileSystem) fsName(filename string) (string, bool) {
+	if filename == fileSystemRoot {
+		return ".", true
+	}
+	name, ok := strings.CutPrefix(filename, fileSystemRoot+"/")
+	if !ok {
+		return "", false
+	}
+	return path.Clean(name), true
+}
+
+// open opens filename with PROJ's access mode.
+func (s *fileSystem) open(filename string, access int) (fileSystemFile, error) {
+	name, ok := s.fsName(filename)
+	if !ok {
+		switch access {
+		case fileAccessReadUpdate:
+			return os.OpenFile(filename, os.O_RDWR, 0)
+		case fileAccessCreate:
+			return os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+		default:
+			return os.Open(filename)
+		}
+	}
+
+	if access != fileAccessReadOnly {
+		return nil, fs.ErrPermission
+	}
+	file, err := s.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if readSeekCloser, ok := file.(fileSystemFile); ok {
+		return readSeekCloser, nil
+	}
+
+	// Files that cannot seek, for example compressed files in zip archives,
+	// are read into memory.
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return readSeekNopCloser{Reader: bytes.NewReader(data)}, nil
+}
+
+// exists returns whether filename exists.
+func (s *fileSystem) exists(filename string) bool {
+	if name, ok := s.fsName(filename); ok {
+		_, err := fs.Stat(s.fsys, name)
+		return err == nil
+	}
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+// cBool returns value as a C boolean.
+func cBool(value bool) C.int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+//export goProjFileOpen
+func goProjFileOpen(fileSystemHandle C.uintptr_t, cFilename *C.char, access C.int) C.uintptr_t {
+	s, ok := cgo.Handle(fileSystemHandle).Value().(*fileSystem)
+	if !ok {
+		return 0
+	}
+	file, err := s.open(C.GoString(cFilename), int(access))
+	if err != nil {
+		return 0
+	}
+	return C.uintptr_t(cgo.NewHandle(file))
+}
+
+//export goProjFileRead
+func goProjFileRead(fileHandle C.uintptr_t, buffer unsafe.Pointer, size C.size_t) C.size_t {
+	file, ok := cgo.Handle(fileHandle).Value().(fileSystemFile)
+	if !ok || size == 0 {
+		return 0
+	}
+	n, _ := io.ReadFull(file, unsafe.Slice((*byte)(buffer), size))
+	return C.size_t(n)
+}
+
+//export goProjFileWrite
+func goProjFileWrite(fileHandle C.uintptr_t, buffer unsafe.Pointer, size C.size_t) C.size_t {
+	writer, ok := cgo.Handle(fileHandle).Value().(io.Writer)
+	if !ok || size == 0 {
+		return 0
+	}
+	n, _ := writer.Write(unsafe.Slice((*byte)(buffer), size))
+	return C.size_t(n)
+}
+
+//export goProjFileSeek
+func goProjFileSeek(fileHandle C.uintptr_t, offset C.longlong, whence C.int) C.int {
+	file, ok := cgo.Handle(fileHandle).Value().(fileSystemFile)
+	if !ok {
+		return 0
+	}
+	_, err := file.Seek(int64(offset), int(whence))
+	return cBool(err == nil)
+}
+
+//export goProjFileTell
+func goProjFileTell(fileHandle C.uintptr_t) C.ulonglong {
+	file, ok := cgo.Handle(fileHandle).Value().(fileSystemFile)
+	if !ok {
+		return 0
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0
+	}
+	return C.ulonglong(offset)
+}
+
+//export goProjFileClose
+func goProjFileClose(fileHandle C.uintptr_t) {
+	handle := cgo.Handle(fileHandle)
+	if file, ok := handle.Value().(fileSystemFile); ok {
+		_ = file.Close()
+	}
+	handle.Delete()
+}
+
+//export goProjFileExists
+func goProjFileExists(fileSystemHandle C.uintptr_t, cFilename *C.char) C.int {
+	s, ok := cgo.Handle(fileSystemHandle).Value().(*fileSystem)
+	if !ok {
+		return 0
+	}
+	return cBool(s.exists(C.GoString(cFilename)))
+}
+
+//export goProjFileMkdir
+func goProjFileMkdir(fileSystemHandle C.uintptr_t, cFilename *C.char) C.int {
+	s, ok := cgo.Handle(fileSystemHandle).Value().(*fileSystem)
+	if !ok {
+		return 0
+	}
+	filename := C.GoString(cFilename)
+	if _, ok := s.fsName(filename); ok {
+		return 0
+	}
+	err := os.Mkdir(filename, 0o755)
+	return cBool(err == nil || errors.Is(err, fs.ErrExist))
+}
+
+//export goProjFileUnlink
+func goProjFileUnlink(fileSystemHandle C.uintptr_t, cFilename *C.char) C.int {
+	s, ok := cgo.Handle(fileSystemHandle).Value().(*fileSystem)
+	if !ok {
+		return 0
+	}
+	filename := C.GoString(cFilename)
+	if _, ok := s.fsName(filename); ok {
+		return 0
+	}
+	return cBool(os.Remove(filename) == nil)
+}
+
+//export goProjFileRename
+func goProjFileRename(fileSystemHandle C.uintptr_t, cOldPath, cNewPath *C.char) C.int {
+	s, ok := cgo.Handle(fileSystemHandle).Value().(*fileSystem)
+	if !ok {
+		return 0
+	}
+	oldPath, newPath := C.GoString(cOldPath), C.GoString(cNewPath)
+	if _, ok := s.fsName(oldPath); ok {
+		return 0
+	}
+	if _, ok := s.fsName(newPath); ok {
+		return 0
+	}
+	return cBool(os.Rename(oldPath, newPath) == nil)
+}
@@ -0,0 +1,47 @@
+package proj_test
+
+import (
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+func TestContext_SetFileSystem(t *testing.T) {
+	if !proj.Capabilities().FileAPI {
+		t.Skip("file API not supported")
+	}
+
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	assert.NoError(t, context.SetFileSystem(fstest.MapFS{
+		"go_proj_test_init": &fstest.MapFile{
+			Data: []byte("<utm32> +proj=utm +zone=32 +ellps=GRS80 +units=m <>\n"),
+		},
+	}))
+
+	pj, err := context.New("+init=go_proj_test_init:utm32")
+	assert.NoError(t, err)
+	assert.NotZero(t, pj)
+
+	coord := proj.NewCoord(9, 47, 0, 0)
+	projectedCoord, err := pj.Forward(coord.DegToRad())
+	assert.NoError(t, err)
+	assertInDelta(t, 500000, projectedCoord.X(), 1e-3)
+
+	_, err = context.New("+init=go_proj_test_missing_init:utm32")
+	assert.Error(t, err)
+
+	// The database is still available.
+	pj, err = context.New("EPSG:2056")
+	assert.NoError(t, err)
+	assert.NotZero(t, pj)
+
+	assert.Error(t, context.SetFileSystem(nil))
+}
@@ -0,0 +1,453 @@
+package proj
+
+// #include <stdlib.h>
+// #include <geodesic.h>
+// #include "go-proj.h"
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unsafe"
+)
+
+// corridorArcStep is the maximum angle, in degrees, between consecutive
+// points on the round caps and joins of GeodesicCorridor.
+const corridorArcStep = 360. / 32
+
+// corridorMaxMiter is the maximum ratio between the distance of an inner
+// corner of GeodesicCorridor and its half width.
+const corridorMaxMiter = 4
+
+// A Geodesic solves geodesic problems on an ellipsoid. Latitudes, longitudes,
+// and azimuths are in degrees, and distances in meters. A Geodesic is safe for
+// concurrent use.
+type Geodesic struct {
+	a float64
+	f float64
+	g C.struct_geod_geodesic
+}
+
+// A GeodesicSolution is the solution of a geodesic problem between two points.
+type GeodesicSolution struct {
+	Lat1            float64 // Latitude of the first point.
+	Lon1            float64 // Longitude of the first point.
+	Azimuth1        float64 // Azimuth at the first point.
+	Lat2            float64 // Latitude of the second point.
+	Lon2            float64 // Longitude of the second point.
+	Azimuth2        float64 // Forward azimuth at the second point.
+	Distance        float64 // Distance between the points.
+	Arc             float64 // Arc length on the auxiliary sphere, in degrees.
+	ReducedLength   float64 // Reduced length of the geodesic, in meters.
+	GeodesicScale12 float64 // Geodesic scale of the second point relative to the first.
+	GeodesicScale21 float64 // Geodesic scale of the first point relative to the second.
+	Area            float64 // Area between the geodesic and the equator, in square meters.
+}
+
+// A GeodesicLine is a geodesic starting at a point with an azimuth, along
+// which positions can be computed efficiently.
+type GeodesicLine struct {
+	l C.struct_geod_geodesicline
+}
+
+// A GeodesicPoint is a point on a GeodesicLine.
+type GeodesicPoint struct {
+	Lat      float64 // Latitude.
+	Lon      float64 // Longitude.
+	Azimuth  float64 // Forward azimuth of the line at the point.
+	Distance float64 // Distance from the start of the line.
+}
+
+// A GeodesicPolygon accumulates the vertices or edges of a polygon and
+// computes its area and perimeter. The polygon is closed implicitly and may
+// encircle a pole or cross the antimeridian.
+type GeodesicPolygon struct {
+	g *Geodesic
+	p C.struct_geod_polygon
+}
+
+// NewGeodesic returns a new Geodesic for the ellipsoid with semi-major axis a,
+// in meters, and flattening f. f is zero for a sphere and negative for a
+// prolate ellipsoid.
+func NewGeodesic(a, f float64) (*Geodesic, error) {
+	if !(a > 0) || math.IsInf(a, 0) {
+		return nil, fmt.Errorf("%g: invalid semi-major axis", a)
+	}
+	if !(f < 1) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%g: invalid flattening", f)
+	}
+	g := &Geodesic{
+		a: a,
+		f: f,
+	}
+	C.geod_init(&g.g, (C.double)(a), (C.double)(f))
+	return g, nil
+}
+
+// NewGeodesicFromPJ returns a new Geodesic for the ellipsoid of pj, which may
+// be an ellipsoid, a datum, a CRS, or an operation defined by a PROJ string,
+// such as +proj=merc +ellps=WGS84. The ellipsoid of an operation is the one
+// that Geod and LPDist use, given by the parameters before the first step of
+// a pipeline and defaulting to GRS80.
+func NewGeodesicFromPJ(pj *PJ) (*Geodesic, error) {
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	lastErrno := C.proj_errno_reset(pj.pj)
+	defer C.proj_errno_restore(pj.pj, lastErrno)
+
+	ellipsoid := pj.pj
+	if C.proj_get_type(pj.pj) != C.PJ_TYPE_ELLIPSOID {
+		ellipsoid = C.proj_get_ellipsoid(pj.context.pjContext, pj.pj)
+		if ellipsoid == nil {
+			C.proj_errno_reset(pj.pj)
+			ellipsoid = pj.newDefinitionEllipsoid()
+		}
+		if ellipsoid == nil {
+			if errno := int(C.proj_errno(pj.pj)); errno != 0 {
+				return nil, pj.context.newError(errno)
+			}
+			return nil, fmt.Errorf("no ellipsoid")
+		}
+		defer C.proj_destroy(ellipsoid)
+	}
+
+	var semiMajor, semiMinor, invFlattening C.double
+	var isSemiMinorComputed C.int
+	if C.proj_ellipsoid_get_parameters(pj.context.pjContext, ellipsoid, &semiMajor, &semiMinor, &isSemiMinorComputed, &invFlattening) == 0 {
+		if errno := int(C.proj_errno(pj.pj)); errno != 0 {
+			return nil, pj.context.newError(errno)
+		}
+		return nil, fmt.Errorf("no ellipsoid parameters")
+	}
+
+	var f float64
+	if invFlattening != 0 {
+		f = 1 / float64(invFlattening)
+	}
+	return NewGeodesic(float64(semiMajor), f)
+}
+
+// ellipsoidParameters are the keys of the PROJ string parameters that define
+// an ellipsoid.
+var ellipsoidParameters = map[string]bool{
+	"ellps": true, "datum": true, "a": true, "b": true, "rf": true, "f": true, "es": true, "e": true,
+	"R": true, "R_A": true, "R_V": true, "R_a": true, "R_g": true, "R_h": true, "R_lat_a": true, "R_lat_g": true,
+}
+
+// newDefinitionEllipsoid returns a new ellipsoid object with the ellipsoid
+// parameters of pj's PROJ string definition before the first step, or nil.
+// pj's context must be locked.
+func (pj *PJ) newDefinitionEllipsoid() *C.PJ {
+	args := []string{"+proj=longlat", "+type=crs"}
+	for _, arg := range strings.Fields(C.GoString(C.proj_pj_info(pj.pj).definition)) {
+		arg = strings.TrimPrefix(arg, "+")
+		if arg == "step" {
+			break
+		}
+		if key, _, _ := strings.Cut(arg, "="); ellipsoidParameters[key] {
+			args = append(args, "+"+arg)
+		}
+	}
+
+	cDefinition := C.CString(strings.Join(args, " "))
+	defer C.free(unsafe.Pointer(cDefinition))
+
+	crs := C.proj_create(pj.context.pjContext, cDefinition)
+	if crs == nil {
+		return nil
+	}
+	defer C.proj_destroy(crs)
+	return C.proj_get_ellipsoid(pj.context.pjContext, crs)
+}
+
+// SemiMajorAxis returns the semi-major axis of g's ellipsoid in meters.
+func (g *Geodesic) SemiMajorAxis() float64 {
+	return g.a
+}
+
+// Flattening returns the flattening of g's ellipsoid.
+func (g *Geodesic) Flattening() float64 {
+	return g.f
+}
+
+// Inverse solves the inverse geodesic problem: it returns the shortest
+// geodesic between lat1, lon1 and lat2, lon2.
+func (g *Geodesic) Inverse(lat1, lon1, lat2, lon2 float64) GeodesicSolution {
+	solution := GeodesicSolution{
+		Lat1: lat1,
+		Lon1: lon1,
+		Lat2: lat2,
+		Lon2: lon2,
+	}
+	var s12, azi1, azi2, m12, cM12, cM21, cS12 C.double
+	a12 := C.geod_geninverse(&g.g, (C.double)(lat1), (C.double)(lon1), (C.double)(lat2), (C.double)(lon2),
+		&s12, &azi1, &azi2, &m12, &cM12, &cM21, &cS12)
+	solution.Azimuth1 = float64(azi1)
+	solution.Azimuth2 = float64(azi2)
+	solution.Distance = float64(s12)
+	solution.Arc = float64(a12)
+	solution.ReducedLength = float64(m12)
+	solution.GeodesicScale12 = float64(cM12)
+	solution.GeodesicScale21 = float64(cM21)
+	solution.Area = float64(cS12)
+	return solution
+}
+
+// Direct solves the direct geodesic problem: it returns the geodesic that
+// starts at lat1, lon1 with azimuth azi1 and has length s12.
+func (g *Geodesic) Direct(lat1, lon1, azi1, s12 float64) GeodesicSolution {
+	solution := GeodesicSolution{
+		Lat1:     lat1,
+		Lon1:     lon1,
+		Azimuth1: azi1,
+		Distance: s12,
+	}
+	var lat2, lon2, azi2, m12, cM12, cM21, cS12 C.double
+	a12 := C.geod_gendirect(&g.g, (C.double)(lat1), (C.double)(lon1), (C.double)(azi1), C.GEOD_NOFLAGS, (C.double)(s12),
+		&lat2, &lon2, &azi2, nil, &m12, &cM12, &cM21, &cS12)
+	solution.Lat2 = float64(lat2)
+	solution.Lon2 = float64(lon2)
+	solution.Azimuth2 = float64(azi2)
+	solution.Arc = float64(a12)
+	solution.ReducedLength = float64(m12)
+	solution.GeodesicScale12 = float64(cM12)
+	solution.GeodesicScale21 = float64(cM21)
+	solution.Area = float64(cS12)
+	return solution
+}
+
+// InverseLine returns the GeodesicLine from lat1, lon1 to lat2, lon2.
+func (g *Geodesic) InverseLine(lat1, lon1, lat2, lon2 float64) *GeodesicLine {
+	line := &GeodesicLine{}
+	C.geod_inverseline(&line.l, &g.g, (C.double)(lat1), (C.double)(lon1), (C.double)(lat2), (C.double)(lon2), C.GEOD_ALL)
+	return line
+}
+
+// DirectLine returns the GeodesicLine that starts at lat1, lon1 with azimuth
+// azi1 and has length s12.
+func (g *Geodesic) DirectLine(lat1, lon1, azi1, s12 float64) *GeodesicLine {
+	line := &GeodesicLine{}
+	C.geod_directline(&line.l, &g.g, (C.double)(lat1), (C.double)(lon1), (C.double)(azi1), (C.double)(s12), C.GEOD_ALL)
+	return line
+}
+
+// Distance returns the length of l.
+func (l *GeodesicLine) Distance() float64 {
+	return float64(l.l.s13)
+}
+
+// Position returns the point at distance s12 from the start of l. s12 may be
+// negative or greater than the length of l.
+func (l *GeodesicLine) Position(s12 float64) GeodesicPoint {
+	var lat2, lon2, azi2 C.double
+	C.geod_position(&l.l, (C.double)(s12), &lat2, &lon2, &azi2)
+	return GeodesicPoint{
+		Lat:      float64(lat2),
+		Lon:      float64(lon2),
+		Azimuth:  float64(azi2),
+		Distance: s12,
+	}
+}
+
+// Waypoints returns n equally spaced points along l, including its start and
+// end points. n must be at least 2.
+func (l *GeodesicLine) Waypoints(n int) ([]GeodesicPoint, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("%d: need at least 2 waypoints", n)
+	}
+	distance := l.Distance()
+	waypoints := make([]GeodesicPoint, 0, n)
+	for i := 0; i < n; i++ {
+		waypoints = append(waypoints, l.Position(distance*float64(i)/float64(n-1)))
+	}
+	return waypoints, nil
+}
+
+// PolygonArea returns the signed area, in square meters, and the perimeter of
+// the polygon with vertices points. The X and Y of each point are its
+// longitude and latitude in degrees. The area is positive if the vertices are
+// counter-clockwise.
+func (g *Geodesic) PolygonArea(points []Coord) (float64, float64) {
+	if len(points) == 0 {
+		return 0, 0
+	}
+	lats := make([]C.double, len(points))
+	lons := make([]C.double, len(points))
+	for i, point := range points {
+		lons[i] = (C.double)(point[0])
+		lats[i] = (C.double)(point[1])
+	}
+	var area, perimeter C.double
+	C.geod_polygonarea(&g.g, &lats[0], &lons[0], C.int(len(points)), &area, &perimeter)
+	return float64(area), float64(perimeter)
+}
+
+// PolygonWithHolesArea returns the area, in square meters, and the perimeter
+// of the polygon with the outer ring rings[0] and holes rings[1:]. Unlike
+// PolygonArea, the area is always positive, whatever the orientation of the
+// rings. The perimeter includes the holes.
+func (g *Geodesic) PolygonWithHolesArea(rings [][]Coord) (float64, float64) {
+	var area, perimeter float64
+	for i, ring := range rings {
+		ringArea, ringPerimeter := g.PolygonArea(ring)
+		if i == 0 {
+			area += math.Abs(ringArea)
+		} else {
+			area -= math.Abs(ringArea)
+		}
+		perimeter += ringPerimeter
+	}
+	return area, perimeter
+}
+
+// NewPolygon returns a new empty GeodesicPolygon on g.
+func (g *Geodesic) NewPolygon() *GeodesicPolygon {
+	p := &GeodesicPolygon{
+		g: g,
+	}
+	C.geod_polygon_init(&p.p, 0)
+	return p
+}
+
+// AddPoint adds the vertex point, with X and Y its longitude and latitude in
+// degrees.
+func (p *GeodesicPolygon) AddPoint(point Coord) {
+	C.geod_polygon_addpoint(&p.g.g, &p.p, (C.double)(point[1]), (C.double)(point[0]))
+}
+
+// AddEdge adds the vertex at distance from the last vertex in the direction
+// azimuth. At least one vertex must have been added with AddPoint.
+func (p *GeodesicPolygon) AddEdge(azimuth, distance float64) {
+	C.geod_polygon_addedge(&p.g.g, &p.p, (C.double)(azimuth), (C.double)(distance))
+}
+
+// Len returns the number of vertices of p.
+func (p *GeodesicPolygon) Len() int {
+	return int(p.p.num)
+}
+
+// Compute returns the signed area, in square meters, and the perimeter of p.
+// The area is positive if the vertices are counter-clockwise.
+func (p *GeodesicPolygon) Compute() (float64, float64) {
+	var area, perimeter C.double
+	C.geod_polygon_compute(&p.g.g, &p.p, 0, 1, &area, &perimeter)
+	return float64(area), float64(perimeter)
+}
+
+// TestPoint returns the signed area and the perimeter that p would have if
+// point were added, without adding it.
+func (p *GeodesicPolygon) TestPoint(point Coord) (float64, float64) {
+	var area, perimeter C.double
+	C.geod_polygon_testpoint(&p.g.g, &p.p, (C.double)(point[1]), (C.double)(point[0]), 0, 1, &area, &perimeter)
+	return float64(area), float64(perimeter)
+}
+
+// Clear removes all vertices from p.
+func (p *GeodesicPolygon) Clear() {
+	C.geod_polygon_clear(&p.p)
+}
+
+// Circle returns a closed, counter-clockwise ring of segments points at
+// distance radius from center. The X and Y of center and of the returned
+// points are longitudes and latitudes in degrees.
+func (g *Geodesic) Circle(center Coord, radius float64, segments int) ([]Coord, error) {
+	if segments < 3 {
+		return nil, fmt.Errorf("%d: need at least 3 segments", segments)
+	}
+	if !(radius > 0) || math.IsInf(radius, 0) {
+		return nil, fmt.Errorf("%g: invalid radius", radius)
+	}
+	ring := make([]Coord, 0, segments+1)
+	for i := 0; i < segments; i++ {
+		ring = append(ring, g.offset(center, -360*float64(i)/float64(segments), radius))
+	}
+	return append(ring, ring[0]), nil
+}
+
+// Corridor returns a closed, counter-clockwise ring that approximates all
+// points within halfWidth of the geodesic path through line. Ends and outer
+// corners are rounded and inner corners are mitred. The X and Y of the points
+// of line and of the returned points are longitudes and latitudes in degrees.
+func (g *Geodesic) Corridor(line []Coord, halfWidth float64) ([]Coord, error) {
+	if !(halfWidth > 0) || math.IsInf(halfWidth, 0) {
+		return nil, fmt.Errorf("%g: invalid half width", halfWidth)
+	}
+
+	// Skip repeated points, which have no direction.
+	points := make([]Coord, 0, len(line))
+	for _, point := range line {
+		if len(points) == 0 || point[0] != points[len(points)-1][0] || point[1] != points[len(points)-1][1] {
+			points = append(points, point)
+		}
+	}
+	switch len(points) {
+	case 0:
+		return nil, fmt.Errorf("empty line")
+	case 1:
+		return g.Circle(points[0], halfWidth, int(360/corridorArcStep))
+	}
+
+	// Compute the azimuths at the start and end of each segment.
+	startAzimuths := make([]float64, len(points)-1)
+	endAzimuths := make([]float64, len(points)-1)
+	for i := range startAzimuths {
+		solution := g.Inverse(points[i][1], points[i][0], points[i+1][1], points[i+1][0])
+		startAzimuths[i], endAzimuths[i] = solution.Azimuth1, solution.Azimuth2
+	}
+
+	// Build both sides in the direction of line. An angle of -90 is the left
+	// side and 90 the right side.
+	sides := make([][]Coord, 2)
+	for k, side := range []float64{-90, 90} {
+		sides[k] = append(sides[k], g.offset(points[0], startAzimuths[0]+side, halfWidth))
+		for i := 1; i < len(points)-1; i++ {
+			inAzimuth, outAzimuth := endAzimuths[i-1], startAzimuths[i]
+			turn := math.Remainder(outAzimuth-inAzimuth, 360)
+			if turn*side < 0 {
+				// Outer corner.
+				sides[k] = append(sides[k], g.offset(points[i], inAzimuth+side, halfWidth))
+				sides[k] = g.appendArc(sides[k], points[i], inAzimuth+side, inAzimuth+side+turn, halfWidth)
+				sides[k] = append(sides[k], g.offset(points[i], outAzimuth+side, halfWidth))
+			} else {
+				// Inner corner.
+				miter := math.Min(1/math.Cos(turn/2*math.Pi/180), corridorMaxMiter)
+				sides[k] = append(sides[k], g.offset(points[i], inAzimuth+turn/2+side, miter*halfWidth))
+			}
+		}
+		sides[k] = append(sides[k], g.offset(points[len(points)-1], endAzimuths[len(endAzimuths)-1]+side, halfWidth))
+	}
+
+	// Go forward along the left side, around the end, back along the right
+	// side, and around the start, which is clockwise.
+	ring := sides[0]
+	endAzimuth := endAzimuths[len(endAzimuths)-1]
+	ring = g.appendArc(ring, points[len(points)-1], endAzimuth-90, endAzimuth+90, halfWidth)
+	for i := len(sides[1]) - 1; i >= 0; i-- {
+		ring = append(ring, sides[1][i])
+	}
+	ring = g.appendArc(ring, points[0], startAzimuths[0]+90, startAzimuths[0]+270, halfWidth)
+	ring = append(ring, ring[0])
+
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+	return ring, nil
+}
+
+// offset returns the point at distance from point in the direction azimuth.
+func (g *Geodesic) offset(point Coord, azimuth, distance float64) Coord {
+	solution := g.Direct(point[1], point[0], azimuth, distance)
+	return Coord{solution.Lon2, solution.Lat2, 0, 0}
+}
+
+// appendArc appends the points at distance from center with azimuths strictly
+// between fromAzimuth and toAzimuth to coords.
+func (g *Geodesic) appendArc(coords []Coord, center Coord, fromAzimuth, toAzimuth, distance float64) []Coord {
+	n := int(math.Ceil(math.Abs(toAzimuth-fromAzimuth) / corridorArcStep))
+	for i := 1; i < n; i++ {
+		coords = append(coords, g.offset(center, fromAzimuth+(toAzimuth-fromAzimuth)*float64(i)/float64(n), distance))
+	}
+	return coords
+}
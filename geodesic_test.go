@@ -0,0 +1,409 @@
+package proj_test
+
+import (
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+func TestNewGeodesic(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		a           float64
+		f           float64
+		expectedErr bool
+	}{
+		{name: "wgs84", a: 6378137, f: 1 / 298.257223563},
+		{name: "sphere", a: 6371000, f: 0},
+		{name: "zero_semi_major_axis", a: 0, f: 0, expectedErr: true},
+		{name: "flattening_one", a: 6378137, f: 1, expectedErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			geodesic, err := proj.NewGeodesic(tc.a, tc.f)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.a, geodesic.SemiMajorAxis())
+			assert.Equal(t, tc.f, geodesic.Flattening())
+		})
+	}
+}
+
+func TestNewGeodesicFromPJ(t *testing.T) {
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	crs, err := context.New("EPSG:4326")
+	assert.NoError(t, err)
+
+	geodesic, err := proj.NewGeodesicFromPJ(crs)
+	assert.NoError(t, err)
+	assert.Equal(t, 6378137., geodesic.SemiMajorAxis())
+	assertInDelta(t, 1/298.257223563, geodesic.Flattening(), 1e-15)
+
+	ellipsoid, err := context.New("EPSG:7030")
+	assert.NoError(t, err)
+
+	geodesic, err = proj.NewGeodesicFromPJ(ellipsoid)
+	assert.NoError(t, err)
+	assert.Equal(t, 6378137., geodesic.SemiMajorAxis())
+}
+
+func TestGeodesic_Inverse(t *testing.T) {
+	sphere, err := proj.NewGeodesic(6371000, 0)
+	assert.NoError(t, err)
+
+	solution := sphere.Inverse(0, 0, 0, 90)
+	assertInDelta(t, 6371000*math.Pi/2, solution.Distance, 1e-6)
+	assertInDelta(t, 90, solution.Azimuth1, 1e-9)
+	assertInDelta(t, 90, solution.Azimuth2, 1e-9)
+	assertInDelta(t, 90, solution.Arc, 1e-9)
+
+	wgs84, err := proj.NewGeodesic(6378137, 1/298.257223563)
+	assert.NoError(t, err)
+
+	// Quarter meridian.
+	solution = wgs84.Inverse(0, 0, 90, 0)
+	assertInDelta(t, 10001965.729, solution.Distance, 1e-3)
+	assertInDelta(t, 0, solution.Azimuth1, 1e-9)
+
+	// Quarter of the equator.
+	solution = wgs84.Inverse(0, 0, 0, 90)
+	assertInDelta(t, 6378137*math.Pi/2, solution.Distance, 1e-6)
+
+	// JFK to LHR and back.
+	solution = wgs84.Inverse(40.64, -73.78, 51.47, -0.45)
+	assertInDelta(t, 5551.8e3, solution.Distance, 1e3)
+	assert.True(t, solution.ReducedLength > 0)
+	assert.NotZero(t, solution.Area)
+	reverse := wgs84.Inverse(51.47, -0.45, 40.64, -73.78)
+	assertInDelta(t, solution.Distance, reverse.Distance, 1e-6)
+	assertInDelta(t, -solution.Area, reverse.Area, 1e-3)
+}
+
+func TestGeodesic_Direct(t *testing.T) {
+	geodesic, err := proj.NewGeodesic(6378137, 1/298.257223563)
+	assert.NoError(t, err)
+
+	inverse := geodesic.Inverse(40.64, -73.78, 51.47, -0.45)
+	solution := geodesic.Direct(40.64, -73.78, inverse.Azimuth1, inverse.Distance)
+	assertInDelta(t, 51.47, solution.Lat2, 1e-9)
+	assertInDelta(t, -0.45, solution.Lon2, 1e-9)
+	assertInDelta(t, inverse.Azimuth2, solution.Azimuth2, 1e-9)
+	assertInDelta(t, inverse.Arc, solution.Arc, 1e-9)
+	assertInDelta(t, inverse.ReducedLength, solution.ReducedLength, 1e-3)
+	assertInDelta(t, inverse.Area, solution.Area, 1)
+
+	solution = geodesic.Direct(0, 0, 0, 10001965.729)
+	assertInDelta(t, 90, solution.Lat2, 1e-6)
+}
+
+func TestGeodesicLine_Waypoints(t *testing.T) {
+	geodesic, err := proj.NewGeodesic(6378137, 1/298.257223563)
+	assert.NoError(t, err)
+
+	inverse := geodesic.Inverse(40.64, -73.78, 51.47, -0.45)
+	for _, line := range []*proj.GeodesicLine{
+		geodesic.InverseLine(40.64, -73.78, 51.47, -0.45),
+		geodesic.DirectLine(40.64, -73.78, inverse.Azimuth1, inverse.Distance),
+	} {
+		assertInDelta(t, inverse.Distance, line.Distance(), 1e-6)
+
+		waypoints, err := line.Waypoints(5)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, len(waypoints))
+		assertInDelta(t, 40.64, waypoints[0].Lat, 1e-9)
+		assertInDelta(t, -73.78, waypoints[0].Lon, 1e-9)
+		assertInDelta(t, 51.47, waypoints[4].Lat, 1e-8)
+		assertInDelta(t, -0.45, waypoints[4].Lon, 1e-8)
+		for i := 1; i < len(waypoints); i++ {
+			leg := geodesic.Inverse(waypoints[i-1].Lat, waypoints[i-1].Lon, waypoints[i].Lat, waypoints[i].Lon)
+			assertInDelta(t, line.Distance()/4, leg.Distance, 1e-3)
+			assertInDelta(t, line.Distance()*float64(i)/4, waypoints[i].Distance, 1e-6)
+		}
+	}
+
+	_, err = geodesic.InverseLine(0, 0, 1, 1).Waypoints(1)
+	assert.Error(t, err)
+}
+
+func TestGeodesic_PolygonArea(t *testing.T) {
+	geodesic, err := proj.NewGeodesic(6378137, 1/298.257223563)
+	assert.NoError(t, err)
+
+	// Reference values from GeographicLib's tests.
+	for _, tc := range []struct {
+		name              string
+		points            []proj.Coord
+		expectedArea      float64
+		expectedPerimeter float64
+	}{
+		{
+			name: "north_pole",
+			points: []proj.Coord{
+				{0, 89, 0, 0}, {90, 89, 0, 0}, {180, 89, 0, 0}, {270, 89, 0, 0},
+			},
+			expectedArea:      24952305678.0,
+			expectedPerimeter: 631819.8745,
+		},
+		{
+			name: "south_pole",
+			points: []proj.Coord{
+				{0, -89, 0, 0}, {90, -89, 0, 0}, {180, -89, 0, 0}, {270, -89, 0, 0},
+			},
+			expectedArea:      -24952305678.0,
+			expectedPerimeter: 631819.8745,
+		},
+		{
+			name: "diamond",
+			points: []proj.Coord{
+				{-1, 0, 0, 0}, {0, -1, 0, 0}, {1, 0, 0, 0}, {0, 1, 0, 0},
+			},
+			expectedArea:      24619419146.0,
+			expectedPerimeter: 627598.2731,
+		},
+		{
+			name: "octant",
+			points: []proj.Coord{
+				{0, 90, 0, 0}, {0, 0, 0, 0}, {90, 0, 0, 0},
+			},
+			expectedArea:      63758202715511.0,
+			expectedPerimeter: 30022685,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			area, perimeter := geodesic.PolygonArea(tc.points)
+			assertInDelta(t, tc.expectedArea, area, 1)
+			assertInDelta(t, tc.expectedPerimeter, perimeter, 1e-3)
+
+			polygon := geodesic.NewPolygon()
+			for _, point := range tc.points {
+				polygon.AddPoint(point)
+			}
+			assert.Equal(t, len(tc.points), polygon.Len())
+			area, perimeter = polygon.Compute()
+			assertInDelta(t, tc.expectedArea, area, 1)
+			assertInDelta(t, tc.expectedPerimeter, perimeter, 1e-3)
+		})
+	}
+
+	area, perimeter := geodesic.PolygonArea(nil)
+	assert.Equal(t, 0., area)
+	assert.Equal(t, 0., perimeter)
+}
+
+func TestGeodesic_PolygonArea_Antimeridian(t *testing.T) {
+	geodesic, err := proj.NewGeodesic(6378137, 1/298.257223563)
+	assert.NoError(t, err)
+
+	area, perimeter := geodesic.PolygonArea([]proj.Coord{
+		{-0.5, 10, 0, 0}, {0.5, 10, 0, 0}, {0.5, 11, 0, 0}, {-0.5, 11, 0, 0},
+	})
+	assert.True(t, area > 0)
+
+	antimeridianArea, antimeridianPerimeter := geodesic.PolygonArea([]proj.Coord{
+		{179.5, 10, 0, 0}, {-179.5, 10, 0, 0}, {-179.5, 11, 0, 0}, {179.5, 11, 0, 0},
+	})
+	assertInDelta(t, area, antimeridianArea, 1e-3)
+	assertInDelta(t, perimeter, antimeridianPerimeter, 1e-6)
+}
+
+func TestGeodesic_PolygonWithHolesArea(t *testing.T) {
+	geodesic, err := proj.NewGeodesic(6378137, 1/298.257223563)
+	assert.NoError(t, err)
+
+	outer := []proj.Coord{{0, 0, 0, 0}, {2, 0, 0, 0}, {2, 2, 0, 0}, {0, 2, 0, 0}}
+	hole := []proj.Coord{{0.5, 0.5, 0, 0}, {0.5, 1.5, 0, 0}, {1.5, 1.5, 0, 0}, {1.5, 0.5, 0, 0}}
+	outerArea, outerPerimeter := geodesic.PolygonArea(outer)
+	holeArea, holePerimeter := geodesic.PolygonArea(hole)
+	assert.True(t, holeArea < 0)
+
+	area, perimeter := geodesic.PolygonWithHolesArea([][]proj.Coord{outer, hole})
+	assertInDelta(t, outerArea+holeArea, area, 1e-3)
+	assertInDelta(t, outerPerimeter+holePerimeter, perimeter, 1e-6)
+}
+
+func TestGeodesicPolygon(t *testing.T) {
+	geodesic, err := proj.NewGeodesic(6378137, 1/298.257223563)
+	assert.NoError(t, err)
+
+	polygon := geodesic.NewPolygon()
+	polygon.AddPoint(proj.Coord{0, 0, 0, 0})
+	polygon.AddEdge(90, 100000)
+	polygon.AddEdge(0, 100000)
+	polygon.AddEdge(270, 100000)
+	assert.Equal(t, 4, polygon.Len())
+	area, perimeter := polygon.Compute()
+	assertInDelta(t, 1e10, area, 1e7)
+	assertInDelta(t, 4e5, perimeter, 1e3)
+
+	testArea, testPerimeter := polygon.TestPoint(proj.Coord{-0.5, 0.5, 0, 0})
+	assert.True(t, testArea > area)
+	assert.True(t, testPerimeter > perimeter)
+	assert.Equal(t, 4, polygon.Len())
+
+	polygon.Clear()
+	assert.Equal(t, 0, polygon.Len())
+}
+
+func TestPJ_GeodesicPolygonArea(t *testing.T) {
+	defer runtime.GC()
+
+	// PJs defined by a PROJ string use the ellipsoid of their parameters.
+	for _, definition := range []string{"EPSG:4326", "+proj=merc +ellps=WGS84"} {
+		t.Run(definition, func(t *testing.T) {
+			pj, err := proj.New(definition)
+			assert.NoError(t, err)
+
+			points := []proj.Coord{{-1, 0, 0, 0}, {0, -1, 0, 0}, {1, 0, 0, 0}, {0, 1, 0, 0}}
+			for i := range points {
+				points[i] = points[i].DegToRad()
+			}
+			area, perimeter, err := pj.GeodesicPolygonArea(points)
+			assert.NoError(t, err)
+			assertInDelta(t, 24619419146.0, area, 1)
+			assertInDelta(t, 627598.2731, perimeter, 1e-3)
+		})
+	}
+
+	// The default ellipsoid is GRS80, as for Geod.
+	pj, err := proj.New("+proj=merc")
+	assert.NoError(t, err)
+	geodesic, err := proj.NewGeodesicFromPJ(pj)
+	assert.NoError(t, err)
+	assert.Equal(t, 6378137.0, geodesic.SemiMajorAxis())
+	assertInDelta(t, 1/298.257222101, geodesic.Flattening(), 1e-15)
+}
+
+func TestGeodesic_Circle(t *testing.T) {
+	geodesic, err := proj.NewGeodesic(6378137, 1/298.257223563)
+	assert.NoError(t, err)
+
+	center := proj.Coord{8.541111, 47.374444, 0, 0}
+	ring, err := geodesic.Circle(center, 5000, 72)
+	assert.NoError(t, err)
+	assert.Equal(t, 73, len(ring))
+	assert.Equal(t, ring[0], ring[len(ring)-1])
+	for _, point := range ring {
+		assertInDelta(t, 5000, geodesic.Inverse(center[1], center[0], point[1], point[0]).Distance, 1e-6)
+	}
+
+	area, _ := geodesic.PolygonArea(ring[:len(ring)-1])
+	assertInDelta(t, math.Pi*5000*5000, area, 0.01*math.Pi*5000*5000)
+
+	_, err = geodesic.Circle(center, 5000, 2)
+	assert.Error(t, err)
+	_, err = geodesic.Circle(center, 0, 36)
+	assert.Error(t, err)
+}
+
+func TestGeodesic_Corridor(t *testing.T) {
+	geodesic, err := proj.NewGeodesic(6378137, 1/298.257223563)
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		line []proj.Coord
+	}{
+		{
+			name: "straight",
+			line: []proj.Coord{{0, 0, 0, 0}, {1, 0, 0, 0}},
+		},
+		{
+			name: "right_turn",
+			line: []proj.Coord{{0, 0, 0, 0}, {1, 0, 0, 0}, {1, -1, 0, 0}},
+		},
+		{
+			name: "left_turn",
+			line: []proj.Coord{{0, 0, 0, 0}, {1, 0, 0, 0}, {1, 0, 0, 0}, {1, 1, 0, 0}},
+		},
+		{
+			name: "antimeridian",
+			line: []proj.Coord{{179.5, 10, 0, 0}, {-179.5, 10, 0, 0}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			halfWidth := 1000.
+			ring, err := geodesic.Corridor(tc.line, halfWidth)
+			assert.NoError(t, err)
+			assert.Equal(t, ring[0], ring[len(ring)-1])
+
+			var length float64
+			for i := 1; i < len(tc.line); i++ {
+				length += geodesic.Inverse(tc.line[i-1][1], tc.line[i-1][0], tc.line[i][1], tc.line[i][0]).Distance
+			}
+			expectedArea := 2*halfWidth*length + math.Pi*halfWidth*halfWidth
+			area, _ := geodesic.PolygonArea(ring[:len(ring)-1])
+			assertInDelta(t, expectedArea, area, 0.01*expectedArea)
+
+			// The ends are rounded.
+			start, end := tc.line[0], tc.line[len(tc.line)-1]
+			for _, point := range ring {
+				startDistance := geodesic.Inverse(start[1], start[0], point[1], point[0]).Distance
+				endDistance := geodesic.Inverse(end[1], end[0], point[1], point[0]).Distance
+				assert.True(t, startDistance > halfWidth-1e-6 || endDistance > halfWidth-1e-6)
+			}
+		})
+	}
+
+	ring, err := geodesic.Corridor([]proj.Coord{{0, 0, 0, 0}, {0, 0, 0, 0}}, 1000)
+	assert.NoError(t, err)
+	area, _ := geodesic.PolygonArea(ring[:len(ring)-1])
+	assertInDelta(t, math.Pi*1000*1000, area, 0.01*math.Pi*1000*1000)
+
+	_, err = geodesic.Corridor(nil, 1000)
+	assert.Error(t, err)
+	_, err = geodesic.Corridor([]proj.Coord{{0, 0, 0, 0}, {1, 0, 0, 0}}, 0)
+	assert.Error(t, err)
+}
+
+func TestPJ_GeodesicCircle(t *testing.T) {
+	defer runtime.GC()
+
+	pj, err := proj.New("+proj=merc +ellps=WGS84")
+	assert.NoError(t, err)
+
+	center := proj.Coord{8.541111, 47.374444, 0, 0}
+	ring, err := pj.GeodesicCircle(center.DegToRad(), 5000, 36)
+	assert.NoError(t, err)
+	assert.Equal(t, 37, len(ring))
+	for _, point := range ring {
+		distance, _, _ := pj.Geod(center.DegToRad(), point)
+		assertInDelta(t, 5000, distance, 1e-6)
+	}
+
+	line := []proj.Coord{{8, 47, 0, 0}, {9, 47, 0, 0}}
+	for i := range line {
+		line[i] = line[i].DegToRad()
+	}
+	ring, err = pj.GeodesicCorridor(line, 5000)
+	assert.NoError(t, err)
+	assert.NotZero(t, ring)
+	for _, point := range ring {
+		assert.True(t, math.Abs(point.Y()-47*math.Pi/180) < 0.1*math.Pi/180)
+	}
+
+	// Rings are converted to degrees before they are reprojected.
+	crsToCRS, err := proj.NewCRSToCRS("EPSG:4326", "EPSG:2056", nil)
+	assert.NoError(t, err)
+	crsToCRS, err = crsToCRS.NormalizeForVisualization()
+	assert.NoError(t, err)
+	ring, err = pj.GeodesicCircle(center.DegToRad(), 5000, 36)
+	assert.NoError(t, err)
+	projectedRing := append([]proj.Coord{center}, ring...)
+	for i := 1; i < len(projectedRing); i++ {
+		projectedRing[i] = projectedRing[i].RadToDeg()
+	}
+	assert.NoError(t, crsToCRS.ForwardArray(projectedRing))
+	for _, point := range projectedRing[1:] {
+		assertInDelta(t, 5000, math.Hypot(point.X()-projectedRing[0].X(), point.Y()-projectedRing[0].Y()), 1)
+	}
+}
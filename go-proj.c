@@ -1,5 +1,309 @@
 #include "go-proj.h"
 
+#include <math.h>
+#include <string.h>
+
+extern void goProjLogFunc(uintptr_t log_handle, int level, char *msg);
+
+static void go_proj_log_func(void *app_data, int level, const char *msg) {
+  goProjLogFunc((uintptr_t)app_data, level, (char *)msg);
+}
+
+void go_proj_set_log_func(PJ_CONTEXT *ctx, uintptr_t log_handle) {
+  proj_log_func(ctx, (void *)log_handle, go_proj_log_func);
+}
+
+void go_proj_geod_array(PJ *P, size_t n, const PJ_COORD *a, const PJ_COORD *b,
+                        double *out) {
+  for (size_t i = 0; i < n; i++) {
+    PJ_COORD geod = proj_geod(P, a[i], b[i]);
+    out[3 * i] = geod.geod.s;
+    out[3 * i + 1] = geod.geod.a1;
+    out[3 * i + 2] = geod.geod.a2;
+  }
+}
+
+double go_proj_path_length(PJ *P, size_t n, const PJ_COORD *coords) {
+  double length = 0;
+  for (size_t i = 1; i < n; i++) {
+    length += proj_lp_dist(P, coords[i - 1], coords[i]);
+  }
+  return length;
+}
+
+void go_proj_distance_matrix(PJ *P, size_t n, const PJ_COORD *coords,
+                             double *out) {
+  for (size_t i = 0; i < n; i++) {
+    out[i * n + i] = 0;
+    for (size_t j = i + 1; j < n; j++) {
+      double distance = proj_lp_dist(P, coords[i], coords[j]);
+      out[i * n + j] = distance;
+      out[j * n + i] = distance;
+    }
+  }
+}
+
+void go_proj_factors_array(PJ *P, size_t n, const PJ_COORD *coords,
+                           double *out, int *errnos) {
+  for (size_t i = 0; i < n; i++) {
+    proj_errno_reset(P);
+    PJ_FACTORS factors = proj_factors(P, coords[i]);
+    errnos[i] = proj_errno(P);
+    double *f = out + 12 * i;
+    f[0] = factors.meridional_scale;
+    f[1] = factors.parallel_scale;
+    f[2] = factors.areal_scale;
+    f[3] = factors.angular_distortion;
+    f[4] = factors.meridian_parallel_angle;
+    f[5] = factors.meridian_convergence;
+    f[6] = factors.tissot_semimajor;
+    f[7] = factors.tissot_semiminor;
+    f[8] = factors.dx_dlam;
+    f[9] = factors.dx_dphi;
+    f[10] = factors.dy_dlam;
+    f[11] = factors.dy_dphi;
+  }
+}
+
+void go_proj_roundtrip_array(PJ *P, PJ_DIRECTION direction, int n_round_trips,
+                             size_t n, const PJ_COORD *coords, double *out,
+                             int *errnos) {
+  for (size_t i = 0; i < n; i++) {
+    PJ_COORD coord = coords[i];
+    proj_errno_reset(P);
+    out[i] = proj_roundtrip(P, direction, n_round_trips, &coord);
+    errnos[i] = proj_errno(P);
+  }
+}
+
+#if PROJ_VERSION_MAJOR >= 7
+extern uintptr_t goProjNetworkOpen(uintptr_t client_handle, char *url,
+                                   unsigned long long offset,
+                                   size_t size_to_read, void *buffer,
+                                   size_t *out_size_read,
+                                   size_t error_string_max_size,
+                                   char *out_error_string);
+extern void goProjNetworkClose(uintptr_t file_handle);
+extern char *goProjNetworkGetHeaderValue(uintptr_t file_handle,
+                                         char *header_name);
+extern size_t goProjNetworkReadRange(uintptr_t file_handle,
+                                     unsigned long long offset,
+                                     size_t size_to_read, void *buffer,
+                                     size_t error_string_max_size,
+                                     char *out_error_string);
+
+static PROJ_NETWORK_HANDLE *
+go_proj_network_open(PJ_CONTEXT *ctx, const char *url,
+                     unsigned long long offset, size_t size_to_read,
+                     void *buffer, size_t *out_size_read,
+                     size_t error_string_max_size, char *out_error_string,
+                     void *user_data) {
+  return (PROJ_NETWORK_HANDLE *)goProjNetworkOpen(
+      (uintptr_t)user_data, (char *)url, offset, size_to_read, buffer,
+      out_size_read, error_string_max_size, out_error_string);
+}
+
+static void go_proj_network_close(PJ_CONTEXT *ctx, PROJ_NETWORK_HANDLE *handle,
+                                  void *user_data) {
+  goProjNetworkClose((uintptr_t)handle);
+}
+
+static const char *go_proj_network_get_header_value(PJ_CONTEXT *ctx,
+                                                    PROJ_NETWORK_HANDLE *handle,
+                                                    const char *header_name,
+                                                    void *user_data) {
+  return goProjNetworkGetHeaderValue((uintptr_t)handle, (char *)header_name);
+}
+
+static size_t go_proj_network_read_range(PJ_CONTEXT *ctx,
+                                         PROJ_NETWORK_HANDLE *handle,
+                                         unsigned long long offset,
+                                         size_t size_to_read, void *buffer,
+                                         size_t error_string_max_size,
+                                         char *out_error_string,
+                                         void *user_data) {
+  return goProjNetworkReadRange((uintptr_t)handle, offset, size_to_read, buffer,
+                                error_string_max_size, out_error_string);
+}
+
+int go_proj_set_network_callbacks(PJ_CONTEXT *ctx, uintptr_t client_handle) {
+  return proj_context_set_network_callbacks(
+      ctx, go_proj_network_open, go_proj_network_close,
+      go_proj_network_get_header_value, go_proj_network_read_range,
+      (void *)client_handle);
+}
+
+extern int goProjDownloadProgress(uintptr_t progress_handle, double pct);
+
+static int go_proj_download_progress(PJ_CONTEXT *ctx, double pct,
+                                     void *user_data) {
+  return goProjDownloadProgress((uintptr_t)user_data, pct);
+}
+
+int go_proj_download_file(PJ_CONTEXT *ctx, const char *url_or_filename,
+                          int ignore_ttl_setting, uintptr_t progress_handle) {
+  return proj_download_file(ctx, url_or_filename, ignore_ttl_setting,
+                            progress_handle ? go_proj_download_progress : NULL,
+                            (void *)progress_handle);
+}
+
+extern uintptr_t goProjFileOpen(uintptr_t file_system_handle, char *filename,
+                                int access);
+extern size_t goProjFileRead(uintptr_t file_handle, void *buffer, size_t size);
+extern size_t goProjFileWrite(uintptr_t file_handle, void *buffer,
+                              size_t size);
+extern int goProjFileSeek(uintptr_t file_handle, long long offset, int whence);
+extern unsigned long long goProjFileTell(uintptr_t file_handle);
+extern void goProjFileClose(uintptr_t file_handle);
+extern int goProjFileExists(uintptr_t file_system_handle, char *filename);
+extern int goProjFileMkdir(uintptr_t file_system_handle, char *filename);
+extern int goProjFileUnlink(uintptr_t file_system_handle, char *filename);
+extern int goProjFileRename(uintptr_t file_system_handle, char *old_path,
+                            char *new_path);
+
+static PROJ_FILE_HANDLE *go_proj_file_open(PJ_CONTEXT *ctx,
+                                           const char *filename,
+                                           PROJ_OPEN_ACCESS access,
+                                           void *user_data) {
+  return (PROJ_FILE_HANDLE *)goProjFileOpen((uintptr_t)user_data,
+                                            (char *)filename, (int)access);
+}
+
+static size_t go_proj_file_read(PJ_CONTEXT *ctx, PROJ_FILE_HANDLE *handle,
+                                void *buffer, size_t size, void *user_data) {
+  return goProjFileRead((uintptr_t)handle, buffer, size);
+}
+
+static size_t go_proj_file_write(PJ_CONTEXT *ctx, PROJ_FILE_HANDLE *handle,
+                                 const void *buffer, size_t size,
+                                 void *user_data) {
+  return goProjFileWrite((uintptr_t)handle, (void *)buffer, size);
+}
+
+static int go_proj_file_seek(PJ_CONTEXT *ctx, PROJ_FILE_HANDLE *handle,
+                             long long offset, int whence, void *user_data) {
+  return goProjFileSeek((uintptr_t)handle, offset, whence);
+}
+
+static unsigned long long go_proj_file_tell(PJ_CONTEXT *ctx,
+                                            PROJ_FILE_HANDLE *handle,
+                                            void *user_data) {
+  return goProjFileTell((uintptr_t)handle);
+}
+
+static void go_proj_file_close(PJ_CONTEXT *ctx, PROJ_FILE_HANDLE *handle,
+                               void *user_data) {
+  goProjFileClose((uintptr_t)handle);
+}
+
+static int go_proj_file_exists(PJ_CONTEXT *ctx, const char *filename,
+                               void *user_data) {
+  return goProjFileExists((uintptr_t)user_data, (char *)filename);
+}
+
+static int go_proj_file_mkdir(PJ_CONTEXT *ctx, const char *filename,
+                              void *user_data) {
+  return goProjFileMkdir((uintptr_t)user_data, (char *)filename);
+}
+
+static int go_proj_file_unlink(PJ_CONTEXT *ctx, const char *filename,
+                               void *user_data) {
+  return goProjFileUnlink((uintptr_t)user_data, (char *)filename);
+}
+
+static int go_proj_file_rename(PJ_CONTEXT *ctx, const char *old_path,
+                               const char *new_path, void *user_data) {
+  return goProjFileRename((uintptr_t)user_data, (char *)old_path,
+                          (char *)new_path);
+}
+
+static const PROJ_FILE_API go_proj_file_api = {
+    1,
+    go_proj_file_open,
+    go_proj_file_read,
+    go_proj_file_write,
+    go_proj_file_seek,
+    go_proj_file_tell,
+    go_proj_file_close,
+    go_proj_file_exists,
+    go_proj_file_mkdir,
+    go_proj_file_unlink,
+    go_proj_file_rename,
+};
+
+int go_proj_set_fileapi(PJ_CONTEXT *ctx, uintptr_t file_system_handle) {
+  return proj_context_set_fileapi(ctx, &go_proj_file_api,
+                                  (void *)file_system_handle);
+}
+#else
+int go_proj_set_network_callbacks(PJ_CONTEXT *ctx, uintptr_t client_handle) {
+  return 0;
+}
+
+int go_proj_set_fileapi(PJ_CONTEXT *ctx, uintptr_t file_system_handle) {
+  return 0;
+}
+
+int go_proj_download_file(PJ_CONTEXT *ctx, const char *url_or_filename,
+                          int ignore_ttl_setting, uintptr_t progress_handle) {
+  return 0;
+}
+#endif
+
+#if PROJ_VERSION_MAJOR < 7
+const char *proj_context_get_user_writable_directory(PJ_CONTEXT *ctx,
+                                                     int create) {
+  return NULL;
+}
+
+int proj_context_set_enable_network(PJ_CONTEXT *ctx, int enabled) {
+  return 0;
+}
+
+int proj_context_is_network_enabled(PJ_CONTEXT *ctx) { return 0; }
+
+void proj_context_set_url_endpoint(PJ_CONTEXT *ctx, const char *url) {}
+
+const char *proj_context_get_url_endpoint(PJ_CONTEXT *ctx) { return NULL; }
+
+int proj_is_download_needed(PJ_CONTEXT *ctx, const char *url_or_filename,
+                            int ignore_ttl_setting) {
+  return 0;
+}
+
+void proj_grid_cache_set_enable(PJ_CONTEXT *ctx, int enabled) {}
+
+void proj_grid_cache_set_filename(PJ_CONTEXT *ctx, const char *fullname) {}
+
+void proj_grid_cache_set_max_size(PJ_CONTEXT *ctx, int max_size_MB) {}
+
+void proj_grid_cache_set_ttl(PJ_CONTEXT *ctx, int ttl_seconds) {}
+
+void proj_grid_cache_clear(PJ_CONTEXT *ctx) {}
+#endif
+
+#if PROJ_VERSION_MAJOR < 7 ||                                                  \
+    (PROJ_VERSION_MAJOR == 7 && PROJ_VERSION_MINOR < 1)
+int proj_degree_input(PJ *P, enum PJ_DIRECTION dir) { return 0; }
+
+int proj_degree_output(PJ *P, enum PJ_DIRECTION dir) { return 0; }
+
+PJ *proj_create_vertical_crs_ex(
+    PJ_CONTEXT *ctx, const char *crs_name, const char *datum_name,
+    const char *datum_auth_name, const char *datum_code,
+    const char *linear_units, double linear_units_conv,
+    const char *geoid_model_name, const char *geoid_model_auth_name,
+    const char *geoid_model_code, const PJ *geoid_geog_crs,
+    const char *const *options) {
+  return NULL;
+}
+#endif
+
+#if PROJ_VERSION_MAJOR < 7 ||                                                  \
+    (PROJ_VERSION_MAJOR == 7 && PROJ_VERSION_MINOR < 2)
+void proj_context_set_ca_bundle_path(PJ_CONTEXT *ctx, const char *path) {}
+#endif
+
 #if PROJ_VERSION_MAJOR < 8
 const char *proj_context_errno_string(PJ_CONTEXT *ctx, int err) {
   return proj_errno_string(err);
@@ -12,11 +316,67 @@ int proj_trans_bounds(PJ_CONTEXT *context, PJ *P, PJ_DIRECTION direction,
                       double xmin, double ymin, double xmax, double ymax,
                       double *out_xmin, double *out_ymin, double *out_xmax,
                       double *out_ymax, int densify_pts) {
-  return 1;
+  return 0;
+}
+#endif
+
+#if PROJ_VERSION_MAJOR < 8 ||                                                  \
+    (PROJ_VERSION_MAJOR == 8 && PROJ_VERSION_MINOR < 1)
+PJ_INSERT_SESSION *proj_insert_object_session_create(PJ_CONTEXT *ctx) {
+  return NULL;
+}
+
+void proj_insert_object_session_destroy(PJ_CONTEXT *ctx,
+                                        PJ_INSERT_SESSION *session) {}
+
+PROJ_STRING_LIST proj_get_insert_statements(
+    PJ_CONTEXT *ctx, PJ_INSERT_SESSION *session, const PJ *object,
+    const char *authority, const char *code, int numeric_codes,
+    const char *const *allowed_authorities, const char *const *options) {
+  return NULL;
+}
+
+PROJ_STRING_LIST proj_context_get_database_structure(
+    PJ_CONTEXT *ctx, const char *const *options) {
+  return NULL;
+}
+
+PROJ_STRING_LIST proj_get_geoid_models_from_database(
+    PJ_CONTEXT *ctx, const char *auth_name, const char *code,
+    const char *const *options) {
+  return NULL;
 }
 #endif
 
 #if PROJ_VERSION_MAJOR < 9 ||                                                  \
     (PROJ_VERSION_MAJOR == 9 && PROJ_VERSION_MINOR < 1)
 PJ *proj_trans_get_last_used_operation(PJ *P) { return NULL; }
-#endif
\ No newline at end of file
+#endif
+
+#if PROJ_VERSION_MAJOR < 9 ||                                                  \
+    (PROJ_VERSION_MAJOR == 9 && PROJ_VERSION_MINOR < 2)
+PJ *proj_coordinate_metadata_create(PJ_CONTEXT *ctx, const PJ *crs,
+                                    double epoch) {
+  return NULL;
+}
+
+double proj_coordinate_metadata_get_epoch(PJ_CONTEXT *ctx, const PJ *obj) {
+  return NAN;
+}
+#endif
+
+#if PROJ_VERSION_MAJOR < 9 ||                                                  \
+    (PROJ_VERSION_MAJOR == 9 && PROJ_VERSION_MINOR < 3)
+char *proj_rtodms2(char *s, size_t sizeof_s, double r, int pos, int neg) {
+  // proj_rtodms does not bound its output, but the degrees are an int and the
+  // seconds have a fixed precision, so 64 bytes are always enough.
+  char buffer[64];
+  if (sizeof_s == 0) {
+    return NULL;
+  }
+  proj_rtodms(buffer, r, pos, neg);
+  strncpy(s, buffer, sizeof_s - 1);
+  s[sizeof_s - 1] = '\0';
+  return s;
+}
+#endif
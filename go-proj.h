@@ -2,6 +2,57 @@
 #define GO_PROJ_H
 
 #include <proj.h>
+#include <stdint.h>
+
+void go_proj_set_log_func(PJ_CONTEXT *ctx, uintptr_t log_handle);
+int go_proj_set_network_callbacks(PJ_CONTEXT *ctx, uintptr_t client_handle);
+int go_proj_download_file(PJ_CONTEXT *ctx, const char *url_or_filename,
+                          int ignore_ttl_setting, uintptr_t progress_handle);
+int go_proj_set_fileapi(PJ_CONTEXT *ctx, uintptr_t file_system_handle);
+void go_proj_geod_array(PJ *P, size_t n, const PJ_COORD *a, const PJ_COORD *b,
+                        double *out);
+double go_proj_path_length(PJ *P, size_t n, const PJ_COORD *coords);
+void go_proj_distance_matrix(PJ *P, size_t n, const PJ_COORD *coords,
+                             double *out);
+void go_proj_factors_array(PJ *P, size_t n, const PJ_COORD *coords,
+                           double *out, int *errnos);
+void go_proj_roundtrip_array(PJ *P, PJ_DIRECTION direction, int n_round_trips,
+                             size_t n, const PJ_COORD *coords, double *out,
+                             int *errnos);
+
+#if PROJ_VERSION_MAJOR < 7
+const char *proj_context_get_user_writable_directory(PJ_CONTEXT *ctx,
+                                                     int create);
+int proj_context_set_enable_network(PJ_CONTEXT *ctx, int enabled);
+int proj_context_is_network_enabled(PJ_CONTEXT *ctx);
+void proj_context_set_url_endpoint(PJ_CONTEXT *ctx, const char *url);
+const char *proj_context_get_url_endpoint(PJ_CONTEXT *ctx);
+int proj_is_download_needed(PJ_CONTEXT *ctx, const char *url_or_filename,
+                            int ignore_ttl_setting);
+void proj_grid_cache_set_enable(PJ_CONTEXT *ctx, int enabled);
+void proj_grid_cache_set_filename(PJ_CONTEXT *ctx, const char *fullname);
+void proj_grid_cache_set_max_size(PJ_CONTEXT *ctx, int max_size_MB);
+void proj_grid_cache_set_ttl(PJ_CONTEXT *ctx, int ttl_seconds);
+void proj_grid_cache_clear(PJ_CONTEXT *ctx);
+#endif
+
+#if PROJ_VERSION_MAJOR < 7 ||                                                  \
+    (PROJ_VERSION_MAJOR == 7 && PROJ_VERSION_MINOR < 1)
+int proj_degree_input(PJ *P, enum PJ_DIRECTION dir);
+int proj_degree_output(PJ *P, enum PJ_DIRECTION dir);
+PJ *proj_create_vertical_crs_ex(
+    PJ_CONTEXT *ctx, const char *crs_name, const char *datum_name,
+    const char *datum_auth_name, const char *datum_code,
+    const char *linear_units, double linear_units_conv,
+    const char *geoid_model_name, const char *geoid_model_auth_name,
+    const char *geoid_model_code, const PJ *geoid_geog_crs,
+    const char *const *options);
+#endif
+
+#if PROJ_VERSION_MAJOR < 7 ||                                                  \
+    (PROJ_VERSION_MAJOR == 7 && PROJ_VERSION_MINOR < 2)
+void proj_context_set_ca_bundle_path(PJ_CONTEXT *ctx, const char *path);
+#endif
 
 #if PROJ_VERSION_MAJOR < 8
 const char *proj_context_errno_string(PJ_CONTEXT *ctx, int err);
@@ -15,9 +66,38 @@ int proj_trans_bounds(PJ_CONTEXT *context, PJ *P, PJ_DIRECTION direction,
                       double *out_ymax, int densify_pts);
 #endif
 
+#if PROJ_VERSION_MAJOR < 8 ||                                                  \
+    (PROJ_VERSION_MAJOR == 8 && PROJ_VERSION_MINOR < 1)
+typedef struct PJ_INSERT_SESSION PJ_INSERT_SESSION;
+PJ_INSERT_SESSION *proj_insert_object_session_create(PJ_CONTEXT *ctx);
+void proj_insert_object_session_destroy(PJ_CONTEXT *ctx,
+                                        PJ_INSERT_SESSION *session);
+PROJ_STRING_LIST proj_get_insert_statements(
+    PJ_CONTEXT *ctx, PJ_INSERT_SESSION *session, const PJ *object,
+    const char *authority, const char *code, int numeric_codes,
+    const char *const *allowed_authorities, const char *const *options);
+PROJ_STRING_LIST proj_context_get_database_structure(
+    PJ_CONTEXT *ctx, const char *const *options);
+PROJ_STRING_LIST proj_get_geoid_models_from_database(
+    PJ_CONTEXT *ctx, const char *auth_name, const char *code,
+    const char *const *options);
+#endif
+
 #if PROJ_VERSION_MAJOR < 9 ||                                                  \
     (PROJ_VERSION_MAJOR == 9 && PROJ_VERSION_MINOR < 1)
 PJ *proj_trans_get_last_used_operation(PJ *P);
 #endif
 
+#if PROJ_VERSION_MAJOR < 9 ||                                                  \
+    (PROJ_VERSION_MAJOR == 9 && PROJ_VERSION_MINOR < 2)
+PJ *proj_coordinate_metadata_create(PJ_CONTEXT *ctx, const PJ *crs,
+                                    double epoch);
+double proj_coordinate_metadata_get_epoch(PJ_CONTEXT *ctx, const PJ *obj);
+#endif
+
+#if PROJ_VERSION_MAJOR < 9 ||                                                  \
+    (PROJ_VERSION_MAJOR == 9 && PROJ_VERSION_MINOR < 3)
+char *proj_rtodms2(char *s, size_t sizeof_s, double r, int pos, int neg);
+#endif
+
 #endif
\ No newline at end of file
@@ -2,10 +2,12 @@ module github.com/michiho/go-proj/v10
 
 go 1.21
 
-require github.com/alecthomas/assert/v2 v2.10.0
+require (
+	github.com/alecthomas/assert/v2 v2.10.0
+	github.com/google/go-cmp v0.6.0
+)
 
 require (
 	github.com/alecthomas/repr v0.4.0 // indirect
-	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/hexops/gotextdiff v1.0.3 // indirect
 )
@@ -0,0 +1,407 @@
+package grids
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// TIFF and GeoTIFF tags and keys.
+const (
+	tiffTagNewSubfileType      = 254
+	tiffTagImageWidth          = 256
+	tiffTagImageLength         = 257
+	tiffTagImageDescription    = 270
+	tiffTagSamplesPerPixel     = 277
+	tiffTagModelPixelScale     = 33550
+	tiffTagModelTiepoint       = 33922
+	tiffTagModelTransformation = 34264
+	tiffTagGeoKeyDirectory     = 34735
+	tiffTagGDALMetadata        = 42112
+
+	tiffSubfileTypePage = 2
+
+	geoKeyModelType    = 1024
+	geoKeyRasterType   = 1025
+	modelTypeProjected = 1
+	rasterPixelIsArea  = 1
+)
+
+// maxTIFFValueSize is the maximum size of a TIFF tag value that is read.
+const maxTIFFValueSize = 1 << 24
+
+// tiffTypeSizes maps TIFF field types to their sizes in bytes.
+var tiffTypeSizes = map[uint16]int{
+	1:  1, // BYTE
+	2:  1, // ASCII
+	3:  2, // SHORT
+	4:  4, // LONG
+	5:  8, // RATIONAL
+	6:  1, // SBYTE
+	7:  1, // UNDEFINED
+	8:  2, // SSHORT
+	9:  4, // SLONG
+	10: 8, // SRATIONAL
+	11: 4, // FLOAT
+	12: 8, // DOUBLE
+	16: 8, // LONG8
+	17: 8, // SLONG8
+	18: 8, // IFD8
+}
+
+// A tiffReader reads the image file directories of a TIFF or BigTIFF file.
+type tiffReader struct {
+	r         io.ReaderAt
+	byteOrder binary.ByteOrder
+	bigTIFF   bool
+}
+
+// A tiffField is a field of an image file directory.
+type tiffField struct {
+	fieldType uint16
+	count     uint64
+	data      []byte
+}
+
+// A gdalMetadata is the XML document in the GDAL_METADATA tag.
+type gdalMetadata struct {
+	Items []struct {
+		Name   string `xml:"name,attr"`
+		Sample *int   `xml:"sample,attr"`
+		Role   string `xml:"role,attr"`
+		Value  string `xml:",chardata"`
+	} `xml:"Item"`
+}
+
+// readGeoTIFFHeader reads the header of the PROJ-profile GeoTIFF file r. Each
+// full resolution image is a grid. Grids are nested by their extents.
+func readGeoTIFFHeader(r io.ReaderAt) (*Header, error) {
+	data := make([]byte, 16)
+	if _, err := r.ReadAt(data[:8], 0); err != nil {
+		return nil, err
+	}
+	t := &tiffReader{
+		r: r,
+	}
+	if string(data[:2]) == "MM" {
+		t.byteOrder = binary.BigEndian
+	} else {
+		t.byteOrder = binary.LittleEndian
+	}
+	var offset uint64
+	switch version := t.byteOrder.Uint16(data[2:4]); version {
+	case 42:
+		offset = uint64(t.byteOrder.Uint32(data[4:8]))
+	case 43:
+		t.bigTIFF = true
+		if _, err := r.ReadAt(data, 0); err != nil {
+			return nil, err
+		}
+		offset = t.byteOrder.Uint64(data[8:16])
+	default:
+		return nil, fmt.Errorf("%d: unsupported TIFF version", version)
+	}
+
+	header := &Header{
+		Format: FormatGeoTIFF,
+	}
+	seen := make(map[uint64]bool)
+	for offset != 0 {
+		if seen[offset] {
+			return nil, errors.New("loop in TIFF image file directories")
+		}
+		seen[offset] = true
+
+		fields, nextOffset, err := t.readIFD(offset)
+		if err != nil {
+			return nil, err
+		}
+		offset = nextOffset
+
+		if subfileType := fields.uint(t.byteOrder, tiffTagNewSubfileType, 0); subfileType&^tiffSubfileTypePage != 0 {
+			// Skip overviews and masks.
+			continue
+		}
+		subGrid, err := newGeoTIFFSubGrid(t.byteOrder, fields)
+		if err != nil {
+			return nil, fmt.Errorf("grid %d: %w", len(header.SubGrids), err)
+		}
+		header.SubGrids = append(header.SubGrids, subGrid)
+	}
+	if len(header.SubGrids) == 0 {
+		return nil, errors.New("no grids in GeoTIFF file")
+	}
+
+	for i, subGrid := range header.SubGrids {
+		if parent := smallestContainingSubGrid(header.SubGrids, i); parent != nil {
+			link(parent, subGrid)
+		}
+	}
+
+	return header, nil
+}
+
+// newGeoTIFFSubGrid returns the SubGrid described by the image file directory
+// fields.
+func newGeoTIFFSubGrid(byteOrder binary.ByteOrder, fields tiffFields) (*SubGrid, error) {
+	columns := fields.uint(byteOrder, tiffTagImageWidth, 0)
+	rows := fields.uint(byteOrder, tiffTagImageLength, 0)
+	if columns == 0 || rows == 0 {
+		return nil, errors.New("missing image dimensions")
+	}
+
+	var west, north, lonStep, latStep float64
+	pixelScale := fields.floats(byteOrder, tiffTagModelPixelScale)
+	tiepoint := fields.floats(byteOrder, tiffTagModelTiepoint)
+	transformation := fields.floats(byteOrder, tiffTagModelTransformation)
+	switch {
+	case len(pixelScale) >= 2 && len(tiepoint) >= 6:
+		lonStep, latStep = pixelScale[0], pixelScale[1]
+		west = tiepoint[3] - tiepoint[0]*lonStep
+		north = tiepoint[4] + tiepoint[1]*latStep
+	case len(transformation) >= 8:
+		if transformation[1] != 0 || transformation[4] != 0 {
+			return nil, errors.New("rotated grids are not supported")
+		}
+		lonStep, latStep = transformation[0], -transformation[5]
+		west, north = transformation[3], transformation[7]
+	default:
+		return nil, errors.New("missing georeferencing")
+	}
+	if !(lonStep > 0) || !(latStep > 0) {
+		return nil, errors.New("grid steps must be positive")
+	}
+
+	// GeoTIFF defaults to PixelIsArea, in which case the tie point is the
+	// corner of the first pixel rather than its center.
+	geoKeys := fields.uints(byteOrder, tiffTagGeoKeyDirectory)
+	if geoKeyValue(geoKeys, geoKeyRasterType, rasterPixelIsArea) == rasterPixelIsArea {
+		west += lonStep / 2
+		north -= latStep / 2
+	}
+
+	subGrid := &SubGrid{
+		Grid: Grid{
+			West:    west,
+			South:   north - float64(rows-1)*latStep,
+			LonStep: lonStep,
+			LatStep: latStep,
+			Columns: int(columns),
+			Rows:    int(rows),
+		},
+		Projected:   geoKeyValue(geoKeys, geoKeyModelType, 0) == modelTypeProjected,
+		Description: fields.string(tiffTagImageDescription),
+		Bands:       make([]Band, fields.uint(byteOrder, tiffTagSamplesPerPixel, 1)),
+		Metadata:    make(map[string]string),
+	}
+
+	if gdalMetadataXML := fields.string(tiffTagGDALMetadata); gdalMetadataXML != "" {
+		var metadata gdalMetadata
+		if err := xml.Unmarshal([]byte(gdalMetadataXML), &metadata); err != nil {
+			return nil, fmt.Errorf("GDAL_METADATA: %w", err)
+		}
+		for _, item := range metadata.Items {
+			switch {
+			case item.Sample == nil:
+				subGrid.Metadata[item.Name] = item.Value
+			case *item.Sample < 0 || *item.Sample >= len(subGrid.Bands):
+				return nil, fmt.Errorf("GDAL_METADATA: %s: sample %d out of range", item.Name, *item.Sample)
+			case item.Role == "description":
+				subGrid.Bands[*item.Sample].Description = item.Value
+			case item.Role == "unittype":
+				subGrid.Bands[*item.Sample].Unit = item.Value
+			default:
+				band := &subGrid.Bands[*item.Sample]
+				if band.Metadata == nil {
+					band.Metadata = make(map[string]string)
+				}
+				band.Metadata[item.Name] = item.Value
+			}
+		}
+	}
+	subGrid.Name = subGrid.Metadata["grid_name"]
+	subGrid.Type = subGrid.Metadata["TYPE"]
+
+	return subGrid, nil
+}
+
+// smallestContainingSubGrid returns the smallest grid of subGrids that
+// contains subGrids[i]. Of grids with equal extents, the first contains the
+// others.
+func smallestContainingSubGrid(subGrids []*SubGrid, i int) *SubGrid {
+	var result *SubGrid
+	for j, subGrid := range subGrids {
+		if j == i || !subGrid.containsGrid(&subGrids[i].Grid) {
+			continue
+		}
+		if j > i && subGrids[i].containsGrid(&subGrid.Grid) {
+			continue
+		}
+		if result == nil || result.containsGrid(&subGrid.Grid) {
+			result = subGrid
+		}
+	}
+	return result
+}
+
+// geoKeyValue returns the value of the short GeoKey key in the GeoKey
+// directory geoKeys, or defaultValue if it is not present.
+func geoKeyValue(geoKeys []uint64, key, defaultValue uint64) uint64 {
+	if len(geoKeys) < 4 {
+		return defaultValue
+	}
+	for i := 0; i < int(geoKeys[3]) && 4+4*i+3 < len(geoKeys); i++ {
+		entry := geoKeys[4+4*i : 4+4*i+4]
+		if entry[0] == key && entry[1] == 0 {
+			return entry[3]
+		}
+	}
+	return defaultValue
+}
+
+// A tiffFields maps tags to the fields of an image file directory.
+type tiffFields map[uint16]tiffField
+
+// readIFD reads the image file directory at offset and returns its fields and
+// the offset of the next image file directory.
+func (t *tiffReader) readIFD(offset uint64) (tiffFields, uint64, error) {
+	countSize, entrySize, offsetSize := 2, 12, 4
+	if t.bigTIFF {
+		countSize, entrySize, offsetSize = 8, 20, 8
+	}
+
+	data, err := t.readAt(offset, countSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	count := t.uint(data)
+	if count > maxTIFFValueSize/uint64(entrySize) {
+		return nil, 0, errors.New("too many TIFF fields")
+	}
+	data, err = t.readAt(offset+uint64(countSize), int(count)*entrySize+offsetSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fields := make(tiffFields, count)
+	for i := 0; i < int(count); i++ {
+		entry := data[i*entrySize : (i+1)*entrySize]
+		tag := t.byteOrder.Uint16(entry[0:2])
+		field := tiffField{
+			fieldType: t.byteOrder.Uint16(entry[2:4]),
+			count:     t.uint(entry[4 : 4+offsetSize]),
+		}
+		typeSize, ok := tiffTypeSizes[field.fieldType]
+		if !ok {
+			continue
+		}
+		if field.count > maxTIFFValueSize/uint64(typeSize) {
+			return nil, 0, fmt.Errorf("TIFF tag %d: value too large", tag)
+		}
+		size := int(field.count) * typeSize
+		if value := entry[4+offsetSize:]; size <= len(value) {
+			field.data = value[:size]
+		} else if field.data, err = t.readAt(t.uint(value), size); err != nil {
+			return nil, 0, fmt.Errorf("TIFF tag %d: %w", tag, err)
+		}
+		fields[tag] = field
+	}
+
+	return fields, t.uint(data[int(count)*entrySize:]), nil
+}
+
+// readAt reads size bytes at offset.
+func (t *tiffReader) readAt(offset uint64, size int) ([]byte, error) {
+	if offset > math.MaxInt64 {
+		return nil, errors.New("invalid TIFF offset")
+	}
+	data := make([]byte, size)
+	if _, err := t.r.ReadAt(data, int64(offset)); err != nil {
+		return nil, fmt.Errorf("truncated TIFF file: %w", err)
+	}
+	return data, nil
+}
+
+// uint decodes an unsigned integer of 2, 4, or 8 bytes.
+func (t *tiffReader) uint(data []byte) uint64 {
+	return decodeUint(t.byteOrder, data)
+}
+
+// uints returns the unsigned integer values of the field tag.
+func (fields tiffFields) uints(byteOrder binary.ByteOrder, tag uint16) []uint64 {
+	field, ok := fields[tag]
+	if !ok {
+		return nil
+	}
+	switch field.fieldType {
+	case 1, 3, 4, 16:
+		size := tiffTypeSizes[field.fieldType]
+		values := make([]uint64, field.count)
+		for i := range values {
+			values[i] = decodeUint(byteOrder, field.data[i*size:(i+1)*size])
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// uint returns the first unsigned integer value of the field tag, or
+// defaultValue if it is not present.
+func (fields tiffFields) uint(byteOrder binary.ByteOrder, tag uint16, defaultValue uint64) uint64 {
+	if values := fields.uints(byteOrder, tag); len(values) > 0 {
+		return values[0]
+	}
+	return defaultValue
+}
+
+// floats returns the floating point values of the field tag.
+func (fields tiffFields) floats(byteOrder binary.ByteOrder, tag uint16) []float64 {
+	field, ok := fields[tag]
+	if !ok {
+		return nil
+	}
+	values := make([]float64, field.count)
+	switch field.fieldType {
+	case 11:
+		for i := range values {
+			values[i] = float64(math.Float32frombits(byteOrder.Uint32(field.data[4*i:])))
+		}
+	case 12:
+		for i := range values {
+			values[i] = math.Float64frombits(byteOrder.Uint64(field.data[8*i:]))
+		}
+	default:
+		for i, value := range fields.uints(byteOrder, tag) {
+			values[i] = float64(value)
+		}
+	}
+	return values
+}
+
+// string returns the ASCII value of the field tag.
+func (fields tiffFields) string(tag uint16) string {
+	field, ok := fields[tag]
+	if !ok || field.fieldType != 2 {
+		return ""
+	}
+	return strings.TrimRight(string(field.data), "\x00")
+}
+
+// decodeUint decodes an unsigned integer of 1, 2, 4, or 8 bytes.
+func decodeUint(byteOrder binary.ByteOrder, data []byte) uint64 {
+	switch len(data) {
+	case 1:
+		return uint64(data[0])
+	case 2:
+		return uint64(byteOrder.Uint16(data))
+	case 4:
+		return uint64(byteOrder.Uint32(data))
+	default:
+		return byteOrder.Uint64(data)
+	}
+}
@@ -0,0 +1,171 @@
+package grids_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10/grids"
+)
+
+// A testTIFFField is a field of an image file directory in a test TIFF file.
+type testTIFFField struct {
+	tag       uint16
+	fieldType uint16
+	value     any
+}
+
+// newTestTIFF returns a little endian classic TIFF file with an image file
+// directory for each element of ifds. Image data is omitted.
+func newTestTIFF(tb testing.TB, ifds ...[]testTIFFField) []byte {
+	tb.Helper()
+	data := []byte("II*\x00\x00\x00\x00\x00")
+	nextOffsetIndex := 4
+	for _, fields := range ifds {
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].tag < fields[j].tag
+		})
+
+		// Write the values that do not fit into the directory entries first.
+		values := make([][]byte, len(fields))
+		valueOffsets := make([]int, len(fields))
+		for i, field := range fields {
+			var buffer bytes.Buffer
+			if s, ok := field.value.(string); ok {
+				buffer.WriteString(s + "\x00")
+			} else {
+				assert.NoError(tb, binary.Write(&buffer, binary.LittleEndian, field.value))
+			}
+			values[i] = buffer.Bytes()
+			if len(values[i]) > 4 {
+				valueOffsets[i] = len(data)
+				data = append(data, values[i]...)
+			}
+		}
+
+		binary.LittleEndian.PutUint32(data[nextOffsetIndex:], uint32(len(data)))
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(fields)))
+		for i, field := range fields {
+			count := len(values[i])
+			switch field.fieldType {
+			case 3:
+				count /= 2
+			case 4:
+				count /= 4
+			case 12:
+				count /= 8
+			}
+			data = binary.LittleEndian.AppendUint16(data, field.tag)
+			data = binary.LittleEndian.AppendUint16(data, field.fieldType)
+			data = binary.LittleEndian.AppendUint32(data, uint32(count))
+			if len(values[i]) > 4 {
+				data = binary.LittleEndian.AppendUint32(data, uint32(valueOffsets[i]))
+			} else {
+				data = append(data, append(values[i], make([]byte, 4-len(values[i]))...)...)
+			}
+		}
+		nextOffsetIndex = len(data)
+		data = binary.LittleEndian.AppendUint32(data, 0)
+	}
+	return data
+}
+
+// newTestGeoTIFFFields returns the fields of a horizontal offset grid with two
+// bands.
+func newTestGeoTIFFFields(name string, west, north, step float64, columns, rows uint32) []testTIFFField {
+	return []testTIFFField{
+		{tag: 256, fieldType: 4, value: columns},
+		{tag: 257, fieldType: 4, value: rows},
+		{tag: 270, fieldType: 2, value: "NTv2 " + name},
+		{tag: 277, fieldType: 3, value: uint16(2)},
+		{tag: 33550, fieldType: 12, value: []float64{step, step, 0}},
+		{tag: 33922, fieldType: 12, value: []float64{0, 0, 0, west, north, 0}},
+		{tag: 34735, fieldType: 3, value: []uint16{1, 1, 0, 1, 1025, 0, 1, 2}},
+		{tag: 42112, fieldType: 2, value: `<GDALMetadata>
+  <Item name="grid_name">` + name + `</Item>
+  <Item name="TYPE">HORIZONTAL_OFFSET</Item>
+  <Item name="area_of_use">Test</Item>
+  <Item name="DESCRIPTION" sample="0" role="description">latitude_offset</Item>
+  <Item name="UNITTYPE" sample="0" role="unittype">arc-second</Item>
+  <Item name="DESCRIPTION" sample="1" role="description">longitude_offset</Item>
+  <Item name="UNITTYPE" sample="1" role="unittype">arc-second</Item>
+  <Item name="positive_value" sample="1">east</Item>
+</GDALMetadata>`},
+	}
+}
+
+func TestReadHeader_GeoTIFF(t *testing.T) {
+	overviewFields := newTestGeoTIFFFields("overview", 8, 49, 1, 2, 3)
+	overviewFields = append(overviewFields, testTIFFField{tag: 254, fieldType: 4, value: uint32(1)})
+	data := newTestTIFF(t,
+		newTestGeoTIFFFields("parent", 8, 49, 0.5, 5, 5),
+		overviewFields,
+		newTestGeoTIFFFields("child", 8.5, 48.5, 0.125, 5, 5),
+	)
+
+	header, err := grids.ReadHeader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, grids.FormatGeoTIFF, header.Format)
+	assert.Equal(t, 2, len(header.SubGrids))
+
+	parent := header.SubGrids[0]
+	assert.Equal(t, grids.Grid{West: 8, South: 47, LonStep: 0.5, LatStep: 0.5, Columns: 5, Rows: 5}, parent.Grid)
+	assert.Equal(t, "parent", parent.Name)
+	assert.Equal(t, "HORIZONTAL_OFFSET", parent.Type)
+	assert.Equal(t, "NTv2 parent", parent.Description)
+	assert.Equal(t, "Test", parent.Metadata["area_of_use"])
+	assert.Equal(t, []grids.Band{
+		{Description: "latitude_offset", Unit: "arc-second"},
+		{Description: "longitude_offset", Unit: "arc-second", Metadata: map[string]string{"positive_value": "east"}},
+	}, parent.Bands)
+	assert.Zero(t, parent.Parent)
+
+	child := header.SubGrids[1]
+	assert.Equal(t, grids.Grid{West: 8.5, South: 48, LonStep: 0.125, LatStep: 0.125, Columns: 5, Rows: 5}, child.Grid)
+	assert.Equal(t, "child", child.Name)
+	assert.Equal(t, parent, child.Parent)
+	assert.Equal(t, []*grids.SubGrid{child}, parent.Children)
+
+	assert.Equal(t, child, header.SubGridAt(8.75, 48.25))
+	assert.Equal(t, parent, header.SubGridAt(9.5, 47.5))
+	assert.False(t, header.Contains(7.5, 47.5))
+}
+
+func TestReadHeader_GeoTIFFPixelIsArea(t *testing.T) {
+	fields := newTestGeoTIFFFields("grid", 8, 49, 0.5, 5, 5)
+	for i := range fields {
+		if fields[i].tag == 34735 {
+			fields[i].value = []uint16{1, 1, 0, 1, 1025, 0, 1, 1}
+		}
+	}
+	data := newTestTIFF(t, fields)
+
+	header, err := grids.ReadHeader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, 8.25, header.SubGrids[0].West)
+	assert.Equal(t, 48.75, header.SubGrids[0].North())
+	assert.Equal(t, 46.75, header.SubGrids[0].South)
+}
+
+func TestReadHeader_GeoTIFFProjected(t *testing.T) {
+	fields := newTestGeoTIFFFields("grid", 2600000, 1200000, 1000, 5, 5)
+	for i := range fields {
+		if fields[i].tag == 34735 {
+			fields[i].value = []uint16{1, 1, 0, 2, 1024, 0, 1, 1, 1025, 0, 1, 2}
+		}
+	}
+	data := newTestTIFF(t, fields)
+
+	header, err := grids.ReadHeader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.True(t, header.SubGrids[0].Projected)
+	assert.Equal(t, grids.Grid{West: 2600000, South: 1196000, LonStep: 1000, LatStep: 1000, Columns: 5, Rows: 5}, header.SubGrids[0].Grid)
+
+	data = newTestTIFF(t, newTestGeoTIFFFields("grid", 8, 49, 0.5, 5, 5))
+	header, err = grids.ReadHeader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.False(t, header.SubGrids[0].Projected)
+}
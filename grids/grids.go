@@ -0,0 +1,139 @@
+// Package grids reads and writes shift grids that PROJ can use in
+// transformations, such as local geoid models and horizontal corrections
+// computed from control points.
+package grids
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Grid is a regular grid of nodes in geographic coordinates. Nodes are
+// stored row by row, starting with the southernmost row, and from west to east
+// within each row.
+type Grid struct {
+	West    float64 // Longitude of the westernmost column, in degrees.
+	South   float64 // Latitude of the southernmost row, in degrees.
+	LonStep float64 // Distance between columns, in degrees.
+	LatStep float64 // Distance between rows, in degrees.
+	Columns int     // Number of columns.
+	Rows    int     // Number of rows.
+}
+
+// A VerticalGrid is a Grid of vertical offsets, for use with
+// +proj=vgridshift.
+type VerticalGrid struct {
+	Grid
+	Offsets []float32 // Vertical offsets at each node, in meters.
+}
+
+// A HorizontalGrid is a Grid of horizontal shifts, for use with
+// +proj=hgridshift.
+type HorizontalGrid struct {
+	Grid
+	LonShifts    []float64 // Longitude shifts at each node, in arc seconds, positive east.
+	LatShifts    []float64 // Latitude shifts at each node, in arc seconds, positive north.
+	Name         string    // Name of the grid, at most 8 characters. The default is "GRID".
+	SourceSystem string    // Name of the source datum, at most 8 characters.
+	TargetSystem string    // Name of the target datum, at most 8 characters.
+	Created      time.Time // Creation date of the grid. The default is the current date.
+}
+
+// East returns the longitude of the easternmost column of g, in degrees.
+func (g *Grid) East() float64 {
+	return g.West + float64(g.Columns-1)*g.LonStep
+}
+
+// North returns the latitude of the northernmost row of g, in degrees.
+func (g *Grid) North() float64 {
+	return g.South + float64(g.Rows-1)*g.LatStep
+}
+
+// Len returns the number of nodes of g.
+func (g *Grid) Len() int {
+	return g.Rows * g.Columns
+}
+
+// Index returns the index of the node at row and column of g.
+func (g *Grid) Index(row, column int) int {
+	return row*g.Columns + column
+}
+
+// Node returns the longitude and latitude of the node at row and column of g,
+// in degrees.
+func (g *Grid) Node(row, column int) (float64, float64) {
+	return g.West + float64(column)*g.LonStep, g.South + float64(row)*g.LatStep
+}
+
+// Contains returns whether lon and lat, in degrees, are inside g. Longitudes
+// are compared modulo 360.
+func (g *Grid) Contains(lon, lat float64) bool {
+	if lat < g.South || lat > g.North() {
+		return false
+	}
+	if g.West <= lon && lon <= g.East() {
+		return true
+	}
+	lon = g.West + math.Mod(math.Mod(lon-g.West, 360)+360, 360)
+	return lon <= g.East()
+}
+
+// containsGrid returns whether other is inside g.
+func (g *Grid) containsGrid(other *Grid) bool {
+	return g.West <= other.West && other.East() <= g.East() &&
+		g.South <= other.South && other.North() <= g.North()
+}
+
+// validate returns an error if g is not a valid grid with n nodes.
+func (g *Grid) validate(n int) error {
+	switch {
+	case g.Columns < 2 || g.Rows < 2:
+		return fmt.Errorf("%dx%d: grid must have at least 2 columns and 2 rows", g.Columns, g.Rows)
+	case !(g.LonStep > 0) || !(g.LatStep > 0):
+		return errors.New("grid steps must be positive")
+	case g.South < -90 || g.North() > 90:
+		return fmt.Errorf("%g..%g: latitude out of range", g.South, g.North())
+	case g.West < -360 || g.East() > 360:
+		return fmt.Errorf("%g..%g: longitude out of range", g.West, g.East())
+	case n != g.Len():
+		return fmt.Errorf("%d values for %d nodes", n, g.Len())
+	}
+	return nil
+}
+
+// VGridShiftStep returns a pipeline step that applies the vertical grid in
+// filename, such that z is increased by multiplier times the grid value in
+// the forward direction.
+func VGridShiftStep(filename string, multiplier float64) string {
+	return "+step +proj=vgridshift +grids=" + quote(filename) +
+		" +multiplier=" + strconv.FormatFloat(multiplier, 'g', -1, 64)
+}
+
+// HGridShiftStep returns a pipeline step that applies the horizontal grid in
+// filename.
+func HGridShiftStep(filename string) string {
+	return "+step +proj=hgridshift +grids=" + quote(filename)
+}
+
+// Pipeline returns a pipeline that converts longitudes and latitudes in
+// degrees to radians, applies steps, and converts back to degrees.
+func Pipeline(steps ...string) string {
+	return strings.Join(append(append([]string{
+		"+proj=pipeline",
+		"+step +proj=unitconvert +xy_in=deg +xy_out=rad",
+	}, steps...),
+		"+step +proj=unitconvert +xy_in=rad +xy_out=deg",
+	), " ")
+}
+
+// quote quotes value for use in a PROJ string, if needed.
+func quote(value string) string {
+	if !strings.ContainsAny(value, " \t\"") {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
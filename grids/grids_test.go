@@ -0,0 +1,43 @@
+package grids_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10/grids"
+)
+
+func TestGrid(t *testing.T) {
+	grid := grids.Grid{
+		West:    8,
+		South:   47,
+		LonStep: 0.25,
+		LatStep: 0.5,
+		Columns: 5,
+		Rows:    3,
+	}
+	assert.Equal(t, 9., grid.East())
+	assert.Equal(t, 48., grid.North())
+	assert.Equal(t, 15, grid.Len())
+	assert.Equal(t, 7, grid.Index(1, 2))
+	lon, lat := grid.Node(1, 2)
+	assert.Equal(t, 8.5, lon)
+	assert.Equal(t, 47.5, lat)
+}
+
+func TestVGridShiftStep(t *testing.T) {
+	assert.Equal(t, "+step +proj=vgridshift +grids=/tmp/geoid.gtx +multiplier=1", grids.VGridShiftStep("/tmp/geoid.gtx", 1))
+	assert.Equal(t, `+step +proj=vgridshift +grids="/tmp/my geoid.gtx" +multiplier=-1`, grids.VGridShiftStep("/tmp/my geoid.gtx", -1))
+}
+
+func TestHGridShiftStep(t *testing.T) {
+	assert.Equal(t, "+step +proj=hgridshift +grids=/tmp/shift.gsb", grids.HGridShiftStep("/tmp/shift.gsb"))
+}
+
+func TestPipeline(t *testing.T) {
+	assert.Equal(t,
+		"+proj=pipeline +step +proj=unitconvert +xy_in=deg +xy_out=rad +step +proj=hgridshift +grids=shift.gsb +step +proj=unitconvert +xy_in=rad +xy_out=deg",
+		grids.Pipeline(grids.HGridShiftStep("shift.gsb")),
+	)
+}
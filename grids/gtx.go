@@ -0,0 +1,84 @@
+package grids
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// gtxNoData is the value of nodes without data in GTX files.
+const gtxNoData = -88.8888
+
+// WriteGTX writes g to w in the NOAA GTX format. NaN offsets are written as
+// nodes without data.
+func (g *VerticalGrid) WriteGTX(w io.Writer) error {
+	if err := g.validate(len(g.Offsets)); err != nil {
+		return err
+	}
+
+	buffer := make([]byte, 0, 40+4*len(g.Offsets))
+	buffer = binary.BigEndian.AppendUint64(buffer, math.Float64bits(g.South))
+	buffer = binary.BigEndian.AppendUint64(buffer, math.Float64bits(g.West))
+	buffer = binary.BigEndian.AppendUint64(buffer, math.Float64bits(g.LatStep))
+	buffer = binary.BigEndian.AppendUint64(buffer, math.Float64bits(g.LonStep))
+	buffer = binary.BigEndian.AppendUint32(buffer, uint32(g.Rows))
+	buffer = binary.BigEndian.AppendUint32(buffer, uint32(g.Columns))
+	for _, offset := range g.Offsets {
+		if math.IsNaN(float64(offset)) {
+			offset = gtxNoData
+		}
+		buffer = binary.BigEndian.AppendUint32(buffer, math.Float32bits(offset))
+	}
+	_, err := w.Write(buffer)
+	return err
+}
+
+// WriteGTXFile writes g to the file filename in the NOAA GTX format.
+func (g *VerticalGrid) WriteGTXFile(filename string) error {
+	return writeFile(filename, g.WriteGTX)
+}
+
+// readGTXHeader reads the header of the GTX file r of size bytes.
+func readGTXHeader(r io.ReaderAt, size int64) (*Header, error) {
+	data := make([]byte, 40)
+	if _, err := r.ReadAt(data, 0); err != nil {
+		return nil, fmt.Errorf("not a grid file: %w", err)
+	}
+
+	subGrid := &SubGrid{
+		Grid: Grid{
+			South:   math.Float64frombits(binary.BigEndian.Uint64(data[0:8])),
+			West:    math.Float64frombits(binary.BigEndian.Uint64(data[8:16])),
+			LatStep: math.Float64frombits(binary.BigEndian.Uint64(data[16:24])),
+			LonStep: math.Float64frombits(binary.BigEndian.Uint64(data[24:32])),
+			Rows:    int(int32(binary.BigEndian.Uint32(data[32:36]))),
+			Columns: int(int32(binary.BigEndian.Uint32(data[36:40]))),
+		},
+	}
+	if subGrid.Rows <= 0 || subGrid.Columns <= 0 || !(subGrid.LatStep > 0) || !(subGrid.LonStep > 0) ||
+		40+4*int64(subGrid.Rows)*int64(subGrid.Columns) != size {
+		return nil, errors.New("not a grid file")
+	}
+	// Like PROJ, interpret longitudes of 180 and more as west longitudes.
+	if subGrid.West >= 180 {
+		subGrid.West -= 360
+	}
+
+	return &Header{
+		Format:   FormatGTX,
+		SubGrids: []*SubGrid{subGrid},
+	}, nil
+}
+
+// writeFile writes filename with write.
+func writeFile(filename string, write func(io.Writer) error) error {
+	var buffer bytes.Buffer
+	if err := write(&buffer); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buffer.Bytes(), 0o644)
+}
@@ -0,0 +1,90 @@
+package grids_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10/grids"
+)
+
+func TestVerticalGrid_WriteGTX(t *testing.T) {
+	grid := &grids.VerticalGrid{
+		Grid: grids.Grid{
+			West:    8,
+			South:   47,
+			LonStep: 0.5,
+			LatStep: 0.25,
+			Columns: 3,
+			Rows:    2,
+		},
+		Offsets: []float32{1, 2, 3, 4, 5, float32(math.NaN())},
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, grid.WriteGTX(&buffer))
+	data := buffer.Bytes()
+	assert.Equal(t, 40+4*6, len(data))
+	assert.Equal(t, 47., math.Float64frombits(binary.BigEndian.Uint64(data[0:8])))
+	assert.Equal(t, 8., math.Float64frombits(binary.BigEndian.Uint64(data[8:16])))
+	assert.Equal(t, 0.25, math.Float64frombits(binary.BigEndian.Uint64(data[16:24])))
+	assert.Equal(t, 0.5, math.Float64frombits(binary.BigEndian.Uint64(data[24:32])))
+	assert.Equal(t, uint32(2), binary.BigEndian.Uint32(data[32:36]))
+	assert.Equal(t, uint32(3), binary.BigEndian.Uint32(data[36:40]))
+	for i, expected := range []float32{1, 2, 3, 4, 5, -88.8888} {
+		assert.Equal(t, expected, math.Float32frombits(binary.BigEndian.Uint32(data[40+4*i:])))
+	}
+
+	filename := filepath.Join(t.TempDir(), "grid.gtx")
+	assert.NoError(t, grid.WriteGTXFile(filename))
+	fileData, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, data, fileData)
+}
+
+func TestVerticalGrid_WriteGTX_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		grid grids.VerticalGrid
+	}{
+		{
+			name: "too_few_columns",
+			grid: grids.VerticalGrid{
+				Grid:    grids.Grid{LonStep: 1, LatStep: 1, Columns: 1, Rows: 2},
+				Offsets: []float32{0, 0},
+			},
+		},
+		{
+			name: "zero_step",
+			grid: grids.VerticalGrid{
+				Grid:    grids.Grid{LatStep: 1, Columns: 2, Rows: 2},
+				Offsets: []float32{0, 0, 0, 0},
+			},
+		},
+		{
+			name: "latitude_out_of_range",
+			grid: grids.VerticalGrid{
+				Grid:    grids.Grid{South: 89, LonStep: 1, LatStep: 2, Columns: 2, Rows: 2},
+				Offsets: []float32{0, 0, 0, 0},
+			},
+		},
+		{
+			name: "wrong_number_of_offsets",
+			grid: grids.VerticalGrid{
+				Grid:    grids.Grid{LonStep: 1, LatStep: 1, Columns: 2, Rows: 2},
+				Offsets: []float32{0, 0, 0},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			assert.Error(t, tc.grid.WriteGTX(&buffer))
+			assert.Zero(t, buffer.Len())
+		})
+	}
+}
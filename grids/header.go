@@ -0,0 +1,122 @@
+package grids
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// A Format is a grid file format.
+type Format string
+
+// Formats.
+const (
+	FormatGTX     Format = "gtx"
+	FormatNTv2    Format = "ntv2"
+	FormatGeoTIFF Format = "gtiff"
+)
+
+// A Header describes the grids in a grid file.
+type Header struct {
+	Format       Format
+	SourceSystem string     // Name of the source datum, NTv2 only.
+	TargetSystem string     // Name of the target datum, NTv2 only.
+	SubGrids     []*SubGrid // All grids in the file, in file order.
+}
+
+// A SubGrid describes a grid in a grid file. Extents and steps are in degrees
+// for geographic grids, and in the units of the grid's CRS otherwise.
+type SubGrid struct {
+	Grid
+	Name        string            // Name of the grid: NTv2 SUB_NAME or GeoTIFF grid_name.
+	Type        string            // GeoTIFF TYPE, e.g. "HORIZONTAL_OFFSET" or "GEOGRAPHIC_3D_OFFSET".
+	Projected   bool              // Whether the grid's CRS is projected, GeoTIFF only.
+	Description string            // GeoTIFF image description.
+	Bands       []Band            // GeoTIFF bands.
+	Metadata    map[string]string // GeoTIFF metadata items that do not belong to a band.
+	Parent      *SubGrid          // Grid that contains this grid, or nil.
+	Children    []*SubGrid        // Grids contained in this grid.
+}
+
+// A Band describes a band of a GeoTIFF grid.
+type Band struct {
+	Description string            // Description, e.g. "latitude_offset".
+	Unit        string            // Unit, e.g. "arc-second" or "metre".
+	Metadata    map[string]string // Other metadata items, e.g. positive_value.
+}
+
+// ReadHeader reads the header of the grid file r of size bytes. The format is
+// detected from the contents of r.
+func ReadHeader(r io.ReaderAt, size int64) (*Header, error) {
+	magic := make([]byte, 8)
+	if _, err := r.ReadAt(magic, 0); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("grid file too short")
+		}
+		return nil, err
+	}
+
+	switch string(magic[:4]) {
+	case "II*\x00", "MM\x00*", "II+\x00", "MM\x00+":
+		return readGeoTIFFHeader(r)
+	}
+	if string(magic) == "NUM_OREC" {
+		return readNTv2Header(r, size)
+	}
+	return readGTXHeader(r, size)
+}
+
+// ReadHeaderFile reads the header of the grid file filename.
+func ReadHeaderFile(filename string) (*Header, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return ReadHeader(file, fileInfo.Size())
+}
+
+// Roots returns the grids of h that are not contained in another grid.
+func (h *Header) Roots() []*SubGrid {
+	var roots []*SubGrid
+	for _, subGrid := range h.SubGrids {
+		if subGrid.Parent == nil {
+			roots = append(roots, subGrid)
+		}
+	}
+	return roots
+}
+
+// SubGridAt returns the most detailed grid of h that contains lon and lat, or
+// nil if no grid contains them.
+func (h *Header) SubGridAt(lon, lat float64) *SubGrid {
+	var result *SubGrid
+	candidates := h.Roots()
+FOR:
+	for {
+		for _, candidate := range candidates {
+			if candidate.Contains(lon, lat) {
+				result = candidate
+				candidates = candidate.Children
+				continue FOR
+			}
+		}
+		return result
+	}
+}
+
+// Contains returns whether lon and lat are inside any grid of h.
+func (h *Header) Contains(lon, lat float64) bool {
+	return h.SubGridAt(lon, lat) != nil
+}
+
+// link sets the parent of child and adds child to the children of parent.
+func link(parent, child *SubGrid) {
+	child.Parent = parent
+	parent.Children = append(parent.Children, child)
+}
@@ -0,0 +1,100 @@
+package grids_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10/grids"
+)
+
+func TestReadHeaderFile(t *testing.T) {
+	grid := &grids.VerticalGrid{
+		Grid: grids.Grid{
+			West:    8,
+			South:   47,
+			LonStep: 0.5,
+			LatStep: 0.25,
+			Columns: 3,
+			Rows:    2,
+		},
+		Offsets: []float32{1, 2, 3, 4, 5, 6},
+	}
+	filename := filepath.Join(t.TempDir(), "grid.gtx")
+	assert.NoError(t, grid.WriteGTXFile(filename))
+
+	header, err := grids.ReadHeaderFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, grids.FormatGTX, header.Format)
+	assert.Equal(t, 1, len(header.SubGrids))
+	assert.Equal(t, grid.Grid, header.SubGrids[0].Grid)
+
+	_, err = grids.ReadHeaderFile(filepath.Join(t.TempDir(), "missing.gtx"))
+	assert.Error(t, err)
+}
+
+func TestReadHeader_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "short",
+			data: []byte("NUM_"),
+		},
+		{
+			name: "gtx_size_mismatch",
+			data: bytes.Repeat([]byte{0x3f}, 48),
+		},
+		{
+			name: "truncated_ntv2",
+			data: []byte("NUM_OREC\x0b\x00\x00\x00\x00\x00\x00\x00"),
+		},
+		{
+			name: "truncated_tiff",
+			data: []byte("II*\x00\x08\x00\x00\x00"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := grids.ReadHeader(bytes.NewReader(tc.data), int64(len(tc.data)))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHeader_SubGridAt(t *testing.T) {
+	parent := &grids.SubGrid{
+		Grid: grids.Grid{West: 8, South: 47, LonStep: 1, LatStep: 1, Columns: 3, Rows: 3},
+		Name: "parent",
+	}
+	child := &grids.SubGrid{
+		Grid:   grids.Grid{West: 8.5, South: 47.5, LonStep: 0.25, LatStep: 0.25, Columns: 3, Rows: 3},
+		Name:   "child",
+		Parent: parent,
+	}
+	parent.Children = []*grids.SubGrid{child}
+	header := &grids.Header{
+		SubGrids: []*grids.SubGrid{parent, child},
+	}
+
+	assert.Equal(t, []*grids.SubGrid{parent}, header.Roots())
+	for _, tc := range []struct {
+		lon, lat float64
+		expected *grids.SubGrid
+	}{
+		{lon: 8, lat: 47, expected: parent},
+		{lon: 9, lat: 48, expected: child},
+		{lon: 10, lat: 49, expected: parent},
+		{lon: 370, lat: 48, expected: parent},
+		{lon: 10.5, lat: 48, expected: nil},
+		{lon: 9, lat: 46.5, expected: nil},
+	} {
+		assert.Equal(t, tc.expected, header.SubGridAt(tc.lon, tc.lat))
+		assert.Equal(t, tc.expected != nil, header.Contains(tc.lon, tc.lat))
+	}
+}
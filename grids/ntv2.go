@@ -0,0 +1,272 @@
+package grids
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// GRS80 semi-major and semi-minor axes, written to the NTv2 header.
+const (
+	grs80SemiMajorAxis = 6378137
+	grs80SemiMinorAxis = 6356752.314140356
+)
+
+// An ntv2Writer appends little endian NTv2 records to a buffer.
+type ntv2Writer struct {
+	buffer []byte
+}
+
+// WriteNTv2 writes g to w in the NTv2 format as a single grid.
+func (g *HorizontalGrid) WriteNTv2(w io.Writer) error {
+	if err := g.validate(len(g.LonShifts)); err != nil {
+		return err
+	}
+	if len(g.LatShifts) != len(g.LonShifts) {
+		return fmt.Errorf("%d latitude shifts for %d longitude shifts", len(g.LatShifts), len(g.LonShifts))
+	}
+	name := g.Name
+	if name == "" {
+		name = "GRID"
+	}
+	for _, label := range []string{name, g.SourceSystem, g.TargetSystem} {
+		if len(label) > 8 {
+			return fmt.Errorf("%s: longer than 8 characters", label)
+		}
+	}
+
+	nw := &ntv2Writer{
+		buffer: make([]byte, 0, 16*(11+11+1)+16*g.Len()),
+	}
+
+	// Overview header.
+	nw.int("NUM_OREC", 11)
+	nw.int("NUM_SREC", 11)
+	nw.int("NUM_FILE", 1)
+	nw.string("GS_TYPE", "SECONDS")
+	nw.string("VERSION", "NTv2.0")
+	nw.string("SYSTEM_F", g.SourceSystem)
+	nw.string("SYSTEM_T", g.TargetSystem)
+	nw.float("MAJOR_F", grs80SemiMajorAxis)
+	nw.float("MINOR_F", grs80SemiMinorAxis)
+	nw.float("MAJOR_T", grs80SemiMajorAxis)
+	nw.float("MINOR_T", grs80SemiMinorAxis)
+
+	// Sub-file header. Longitudes are positive west and all values are in arc
+	// seconds.
+	created := g.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	date := created.UTC().Format("20060102")
+	nw.string("SUB_NAME", name)
+	nw.string("PARENT", "NONE")
+	nw.string("CREATED", date)
+	nw.string("UPDATED", date)
+	nw.float("S_LAT", 3600*g.South)
+	nw.float("N_LAT", 3600*g.North())
+	nw.float("E_LONG", -3600*g.East())
+	nw.float("W_LONG", -3600*g.West)
+	nw.float("LAT_INC", 3600*g.LatStep)
+	nw.float("LONG_INC", 3600*g.LonStep)
+	nw.int("GS_COUNT", g.Len())
+
+	// Nodes are ordered from south to north, and from east to west within each
+	// row.
+	for row := 0; row < g.Rows; row++ {
+		for column := g.Columns - 1; column >= 0; column-- {
+			index := g.Index(row, column)
+			nw.node(g.LatShifts[index], -g.LonShifts[index])
+		}
+	}
+
+	nw.string("END", "")
+
+	_, err := w.Write(nw.buffer)
+	return err
+}
+
+// WriteNTv2File writes g to the file filename in the NTv2 format.
+func (g *HorizontalGrid) WriteNTv2File(filename string) error {
+	return writeFile(filename, g.WriteNTv2)
+}
+
+// An ntv2Records maps the keys of NTv2 header records to their values.
+type ntv2Records map[string][]byte
+
+// readNTv2Header reads the header of the NTv2 file r of size bytes.
+func readNTv2Header(r io.ReaderAt, size int64) (*Header, error) {
+	data := make([]byte, 16)
+	if _, err := r.ReadAt(data, 0); err != nil {
+		return nil, err
+	}
+	var byteOrder binary.ByteOrder = binary.LittleEndian
+	if numOrec := binary.LittleEndian.Uint32(data[8:12]); numOrec == 0 || numOrec > 1024 {
+		byteOrder = binary.BigEndian
+	}
+
+	overview, err := readNTv2Records(r, size, 0, int(byteOrder.Uint32(data[8:12])))
+	if err != nil {
+		return nil, err
+	}
+	numOrec, err := overview.int(byteOrder, "NUM_OREC")
+	if err != nil {
+		return nil, err
+	}
+	numSrec, err := overview.int(byteOrder, "NUM_SREC")
+	if err != nil {
+		return nil, err
+	}
+	numFile, err := overview.int(byteOrder, "NUM_FILE")
+	if err != nil {
+		return nil, err
+	}
+	var divisor float64
+	switch gsType := overview.string("GS_TYPE"); gsType {
+	case "SECONDS":
+		divisor = 3600
+	case "MINUTES":
+		divisor = 60
+	case "DEGREES":
+		divisor = 1
+	default:
+		return nil, fmt.Errorf("%s: unsupported GS_TYPE", gsType)
+	}
+
+	header := &Header{
+		Format:       FormatNTv2,
+		SourceSystem: overview.string("SYSTEM_F"),
+		TargetSystem: overview.string("SYSTEM_T"),
+	}
+	subGridsByName := make(map[string]*SubGrid)
+	var parentNames []string
+	offset := 16 * int64(numOrec)
+	for i := 0; i < numFile; i++ {
+		records, err := readNTv2Records(r, size, offset, numSrec)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]float64)
+		for _, key := range []string{"S_LAT", "N_LAT", "E_LONG", "W_LONG", "LAT_INC", "LONG_INC"} {
+			if values[key], err = records.float(byteOrder, key); err != nil {
+				return nil, err
+			}
+		}
+		gsCount, err := records.int(byteOrder, "GS_COUNT")
+		if err != nil {
+			return nil, err
+		}
+		if !(values["LAT_INC"] > 0) || !(values["LONG_INC"] > 0) {
+			return nil, errors.New("NTv2 increments must be positive")
+		}
+
+		subGrid := &SubGrid{
+			Grid: Grid{
+				West:    -values["W_LONG"] / divisor,
+				South:   values["S_LAT"] / divisor,
+				LonStep: values["LONG_INC"] / divisor,
+				LatStep: values["LAT_INC"] / divisor,
+				Columns: int(math.Round((values["W_LONG"]-values["E_LONG"])/values["LONG_INC"])) + 1,
+				Rows:    int(math.Round((values["N_LAT"]-values["S_LAT"])/values["LAT_INC"])) + 1,
+			},
+			Name: records.string("SUB_NAME"),
+		}
+		if subGrid.Columns <= 0 || subGrid.Rows <= 0 || subGrid.Len() != gsCount {
+			return nil, fmt.Errorf("%s: GS_COUNT %d does not match extent", subGrid.Name, gsCount)
+		}
+		header.SubGrids = append(header.SubGrids, subGrid)
+		subGridsByName[subGrid.Name] = subGrid
+		parentNames = append(parentNames, records.string("PARENT"))
+		offset += 16 * (int64(numSrec) + int64(gsCount))
+	}
+
+	for i, subGrid := range header.SubGrids {
+		if parentNames[i] == "NONE" {
+			continue
+		}
+		parent, ok := subGridsByName[parentNames[i]]
+		if !ok || parent == subGrid {
+			return nil, fmt.Errorf("%s: parent %s not found", subGrid.Name, parentNames[i])
+		}
+		link(parent, subGrid)
+	}
+
+	return header, nil
+}
+
+// readNTv2Records reads n records at offset of r of size bytes.
+func readNTv2Records(r io.ReaderAt, size, offset int64, n int) (ntv2Records, error) {
+	if n <= 0 || offset+16*int64(n) > size {
+		return nil, errors.New("truncated NTv2 file")
+	}
+	data := make([]byte, 16*n)
+	if _, err := r.ReadAt(data, offset); err != nil {
+		return nil, err
+	}
+	records := make(ntv2Records, n)
+	for i := 0; i < n; i++ {
+		key := strings.TrimRight(string(data[16*i:16*i+8]), " \x00")
+		records[key] = data[16*i+8 : 16*i+16]
+	}
+	return records, nil
+}
+
+// int returns the integer value of key.
+func (records ntv2Records) int(byteOrder binary.ByteOrder, key string) (int, error) {
+	value, ok := records[key]
+	if !ok {
+		return 0, fmt.Errorf("%s: missing NTv2 record", key)
+	}
+	return int(int32(byteOrder.Uint32(value))), nil
+}
+
+// float returns the floating point value of key.
+func (records ntv2Records) float(byteOrder binary.ByteOrder, key string) (float64, error) {
+	value, ok := records[key]
+	if !ok {
+		return 0, fmt.Errorf("%s: missing NTv2 record", key)
+	}
+	return math.Float64frombits(byteOrder.Uint64(value)), nil
+}
+
+// string returns the string value of key, without padding.
+func (records ntv2Records) string(key string) string {
+	return strings.TrimRight(string(records[key]), " \x00")
+}
+
+// key appends the record key, padded to 8 bytes.
+func (nw *ntv2Writer) key(key string) {
+	nw.buffer = append(nw.buffer, fmt.Sprintf("%-8s", key)...)
+}
+
+// int appends a record with an integer value.
+func (nw *ntv2Writer) int(key string, value int) {
+	nw.key(key)
+	nw.buffer = binary.LittleEndian.AppendUint32(nw.buffer, uint32(value))
+	nw.buffer = binary.LittleEndian.AppendUint32(nw.buffer, 0)
+}
+
+// float appends a record with a floating point value.
+func (nw *ntv2Writer) float(key string, value float64) {
+	nw.key(key)
+	nw.buffer = binary.LittleEndian.AppendUint64(nw.buffer, math.Float64bits(value))
+}
+
+// string appends a record with a string value, padded to 8 bytes.
+func (nw *ntv2Writer) string(key, value string) {
+	nw.key(key)
+	nw.key(value)
+}
+
+// node appends a grid node with the latitude and longitude shifts and zero
+// accuracies.
+func (nw *ntv2Writer) node(latShift, lonShift float64) {
+	nw.buffer = binary.LittleEndian.AppendUint32(nw.buffer, math.Float32bits(float32(latShift)))
+	nw.buffer = binary.LittleEndian.AppendUint32(nw.buffer, math.Float32bits(float32(lonShift)))
+	nw.buffer = binary.LittleEndian.AppendUint32(nw.buffer, 0)
+	nw.buffer = binary.LittleEndian.AppendUint32(nw.buffer, 0)
+}
@@ -0,0 +1,170 @@
+package grids_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10/grids"
+)
+
+func TestHorizontalGrid_WriteNTv2(t *testing.T) {
+	grid := &grids.HorizontalGrid{
+		Grid: grids.Grid{
+			West:    8,
+			South:   47,
+			LonStep: 0.5,
+			LatStep: 0.25,
+			Columns: 3,
+			Rows:    2,
+		},
+		LonShifts:    []float64{1, 2, 3, 4, 5, 6},
+		LatShifts:    []float64{-1, -2, -3, -4, -5, -6},
+		SourceSystem: "LOCAL",
+		TargetSystem: "ETRS89",
+		Created:      time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, grid.WriteNTv2(&buffer))
+	data := buffer.Bytes()
+
+	// The output is reproducible.
+	var otherBuffer bytes.Buffer
+	assert.NoError(t, grid.WriteNTv2(&otherBuffer))
+	assert.Equal(t, data, otherBuffer.Bytes())
+	assert.Equal(t, 16*(11+11+6+1), len(data))
+
+	record := func(i int) (string, []byte) {
+		return string(data[16*i : 16*i+8]), data[16*i+8 : 16*i+16]
+	}
+	intValue := func(i int) int {
+		_, value := record(i)
+		return int(binary.LittleEndian.Uint32(value))
+	}
+	floatValue := func(i int) float64 {
+		_, value := record(i)
+		return math.Float64frombits(binary.LittleEndian.Uint64(value))
+	}
+
+	key, _ := record(0)
+	assert.Equal(t, "NUM_OREC", key)
+	assert.Equal(t, 11, intValue(0))
+	assert.Equal(t, 1, intValue(2))
+	key, value := record(3)
+	assert.Equal(t, "GS_TYPE ", key)
+	assert.Equal(t, "SECONDS ", string(value))
+	_, value = record(5)
+	assert.Equal(t, "LOCAL   ", string(value))
+
+	key, value = record(11)
+	assert.Equal(t, "SUB_NAME", key)
+	assert.Equal(t, "GRID    ", string(value))
+	key, value = record(13)
+	assert.Equal(t, "CREATED ", key)
+	assert.Equal(t, "20240301", string(value))
+	key, value = record(14)
+	assert.Equal(t, "UPDATED ", key)
+	assert.Equal(t, "20240301", string(value))
+	assert.Equal(t, 47*3600., floatValue(15))
+	assert.Equal(t, 47.25*3600, floatValue(16))
+	assert.Equal(t, -9*3600., floatValue(17))
+	assert.Equal(t, -8*3600., floatValue(18))
+	assert.Equal(t, 0.25*3600, floatValue(19))
+	assert.Equal(t, 0.5*3600, floatValue(20))
+	assert.Equal(t, 6, intValue(21))
+
+	// The first node is the south east corner, with its longitude shift
+	// positive west.
+	node := data[16*22:]
+	assert.Equal(t, float32(-3), math.Float32frombits(binary.LittleEndian.Uint32(node[0:4])))
+	assert.Equal(t, float32(-3), math.Float32frombits(binary.LittleEndian.Uint32(node[4:8])))
+	node = data[16*24:]
+	assert.Equal(t, float32(-1), math.Float32frombits(binary.LittleEndian.Uint32(node[0:4])))
+	assert.Equal(t, float32(-1), math.Float32frombits(binary.LittleEndian.Uint32(node[4:8])))
+
+	key, _ = record(28)
+	assert.Equal(t, "END     ", key)
+}
+
+func TestHorizontalGrid_WriteNTv2_Invalid(t *testing.T) {
+	grid := &grids.HorizontalGrid{
+		Grid:      grids.Grid{LonStep: 1, LatStep: 1, Columns: 2, Rows: 2},
+		LonShifts: []float64{0, 0, 0, 0},
+		LatShifts: []float64{0, 0, 0},
+	}
+	var buffer bytes.Buffer
+	assert.Error(t, grid.WriteNTv2(&buffer))
+
+	grid.LatShifts = append(grid.LatShifts, 0)
+	grid.Name = "LONG_GRID_NAME"
+	assert.Error(t, grid.WriteNTv2(&buffer))
+	assert.Zero(t, buffer.Len())
+}
+
+func TestReadHeader_NTv2(t *testing.T) {
+	parent := &grids.HorizontalGrid{
+		Grid: grids.Grid{
+			West:    8,
+			South:   47,
+			LonStep: 0.5,
+			LatStep: 0.25,
+			Columns: 3,
+			Rows:    2,
+		},
+		LonShifts:    make([]float64, 6),
+		LatShifts:    make([]float64, 6),
+		Name:         "PARENT",
+		SourceSystem: "LOCAL",
+		TargetSystem: "ETRS89",
+	}
+	child := &grids.HorizontalGrid{
+		Grid: grids.Grid{
+			West:    8.5,
+			South:   47,
+			LonStep: 0.125,
+			LatStep: 0.125,
+			Columns: 3,
+			Rows:    2,
+		},
+		LonShifts: make([]float64, 6),
+		LatShifts: make([]float64, 6),
+		Name:      "CHILD",
+	}
+
+	var parentBuffer, childBuffer bytes.Buffer
+	assert.NoError(t, parent.WriteNTv2(&parentBuffer))
+	assert.NoError(t, child.WriteNTv2(&childBuffer))
+
+	// Combine both grids into a single file with two sub-files.
+	parentData, childData := parentBuffer.Bytes(), childBuffer.Bytes()
+	data := append([]byte(nil), parentData[:len(parentData)-16]...)
+	binary.LittleEndian.PutUint32(data[16*2+8:], 2)
+	childSubFile := append([]byte(nil), childData[16*11:]...)
+	copy(childSubFile[16*1+8:16*2], "PARENT  ")
+	data = append(data, childSubFile...)
+
+	header, err := grids.ReadHeader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, grids.FormatNTv2, header.Format)
+	assert.Equal(t, "LOCAL", header.SourceSystem)
+	assert.Equal(t, "ETRS89", header.TargetSystem)
+	assert.Equal(t, 2, len(header.SubGrids))
+	assert.Equal(t, "PARENT", header.SubGrids[0].Name)
+	assert.Equal(t, parent.Grid, header.SubGrids[0].Grid)
+	assert.Equal(t, "CHILD", header.SubGrids[1].Name)
+	assert.Equal(t, child.Grid, header.SubGrids[1].Grid)
+	assert.Equal(t, header.SubGrids[0], header.SubGrids[1].Parent)
+	assert.Equal(t, []*grids.SubGrid{header.SubGrids[0]}, header.Roots())
+	assert.Equal(t, header.SubGrids[1], header.SubGridAt(8.6, 47.1))
+	assert.Equal(t, header.SubGrids[0], header.SubGridAt(8.1, 47.1))
+
+	// A missing parent is an error.
+	copy(data[len(parentData)-16+16*1+8:], "MISSING ")
+	_, err = grids.ReadHeader(bytes.NewReader(data), int64(len(data)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package proj_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+	"github.com/michiho/go-proj/v10/grids"
+)
+
+func TestVGridShift(t *testing.T) {
+	defer runtime.GC()
+
+	grid := &grids.VerticalGrid{
+		Grid: grids.Grid{
+			West:    8,
+			South:   47,
+			LonStep: 0.5,
+			LatStep: 0.25,
+			Columns: 4,
+			Rows:    5,
+		},
+	}
+	for i := 0; i < grid.Len(); i++ {
+		grid.Offsets = append(grid.Offsets, 40+float32(i)/8)
+	}
+	filename := filepath.Join(t.TempDir(), "go_proj_test_geoid.gtx")
+	assert.NoError(t, grid.WriteGTXFile(filename))
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	pj, err := context.New(grids.Pipeline(grids.VGridShiftStep(filename, 1)))
+	assert.NoError(t, err)
+
+	for _, node := range [][2]int{{1, 1}, {2, 1}, {3, 2}} {
+		lon, lat := grid.Node(node[0], node[1])
+		coord, err := pj.Forward(proj.NewCoord(lon, lat, 100, 0))
+		assert.NoError(t, err)
+		assertInDelta(t, lon, coord.X(), 1e-9)
+		assertInDelta(t, lat, coord.Y(), 1e-9)
+		assertInDelta(t, 100+float64(grid.Offsets[grid.Index(node[0], node[1])]), coord.Z(), 1e-6)
+	}
+}
+
+func TestHGridShift(t *testing.T) {
+	defer runtime.GC()
+
+	grid := &grids.HorizontalGrid{
+		Grid: grids.Grid{
+			West:    8,
+			South:   47,
+			LonStep: 0.5,
+			LatStep: 0.25,
+			Columns: 4,
+			Rows:    5,
+		},
+	}
+	for i := 0; i < grid.Len(); i++ {
+		grid.LonShifts = append(grid.LonShifts, 1+float64(i)/8)
+		grid.LatShifts = append(grid.LatShifts, -2+float64(i)/16)
+	}
+	filename := filepath.Join(t.TempDir(), "go_proj_test_shift.gsb")
+	assert.NoError(t, grid.WriteNTv2File(filename))
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	pj, err := context.New(grids.Pipeline(grids.HGridShiftStep(filename)))
+	assert.NoError(t, err)
+
+	for _, node := range [][2]int{{1, 1}, {2, 1}, {3, 2}} {
+		lon, lat := grid.Node(node[0], node[1])
+		coord, err := pj.Forward(proj.NewCoord(lon, lat, 0, 0))
+		assert.NoError(t, err)
+		index := grid.Index(node[0], node[1])
+		assertInDelta(t, lon+grid.LonShifts[index]/3600, coord.X(), 1e-9)
+		assertInDelta(t, lat+grid.LatShifts[index]/3600, coord.Y(), 1e-9)
+	}
+}
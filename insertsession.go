@@ -0,0 +1,114 @@
+package proj
+
+// #include <stdlib.h>
+// #include "go-proj.h"
+import "C"
+
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+)
+
+// An InsertSession generates the SQL statements that insert objects into an
+// auxiliary database. Objects inserted in the same session may refer to each
+// other. Load the resulting database with Context.SetDatabasePath.
+type InsertSession struct {
+	context *Context
+	session *C.PJ_INSERT_SESSION
+}
+
+// NewInsertSession returns a new InsertSession.
+func (c *Context) NewInsertSession() (*InsertSession, error) {
+	if !Capabilities().InsertStatements {
+		return nil, ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	cSession := C.proj_insert_object_session_create(c.pjContext)
+	if cSession == nil {
+		return nil, c.newError(int(C.proj_context_errno(c.pjContext)))
+	}
+
+	s := &InsertSession{
+		context: c,
+		session: cSession,
+	}
+	runtime.SetFinalizer(s, (*InsertSession).Destroy)
+	return s, nil
+}
+
+// Destroy frees all resources associated with s.
+func (s *InsertSession) Destroy() {
+	s.context.Lock()
+	defer s.context.Unlock()
+	if s.session != nil {
+		C.proj_insert_object_session_destroy(s.context.pjContext, s.session)
+		s.session = nil
+	}
+}
+
+// InsertStatements returns the SQL statements that insert pj into a database
+// under authority and code, for example "ACME" and "1001". Objects that pj
+// depends on and that are not found in the database are inserted too, with
+// codes derived from code. If numericCodes is true then derived codes are
+// numeric. allowedAuthorities are the authorities that these objects may refer
+// to, defaulting to "EPSG" and "PROJ".
+func (s *InsertSession) InsertStatements(pj *PJ, authority, code string, numericCodes bool, allowedAuthorities []string) ([]string, error) {
+	s.context.Lock()
+	defer s.context.Unlock()
+
+	if s.session == nil {
+		return nil, errors.New("insert session destroyed")
+	}
+
+	if pj.context != s.context {
+		pj.context.Lock()
+		defer pj.context.Unlock()
+	}
+
+	cAuthority := C.CString(authority)
+	defer C.free(unsafe.Pointer(cAuthority))
+
+	cCode := C.CString(code)
+	defer C.free(unsafe.Pointer(cCode))
+
+	var cNumericCodes C.int
+	if numericCodes {
+		cNumericCodes = 1
+	}
+
+	cAllowedAuthorities, freeCAllowedAuthorities := newCStringList(allowedAuthorities)
+	defer freeCAllowedAuthorities()
+
+	cStatements := C.proj_get_insert_statements(s.context.pjContext, s.session, pj.pj, cAuthority, cCode, cNumericCodes, cAllowedAuthorities, nil)
+	if cStatements == nil {
+		return nil, s.context.newError(int(C.proj_context_errno(s.context.pjContext)))
+	}
+	defer C.proj_string_list_destroy(cStatements)
+
+	return nullTerminatedListToGoSlice(cStatements), nil
+}
+
+// DatabaseStructure returns the SQL statements that create the structure of
+// the PROJ database, without its content. Executing them in an empty SQLite
+// database followed by the statements of an InsertSession creates an
+// auxiliary database.
+func (c *Context) DatabaseStructure() ([]string, error) {
+	if !Capabilities().InsertStatements {
+		return nil, ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	cStatements := C.proj_context_get_database_structure(c.pjContext, nil)
+	if cStatements == nil {
+		return nil, c.newError(int(C.proj_context_errno(c.pjContext)))
+	}
+	defer C.proj_string_list_destroy(cStatements)
+
+	return nullTerminatedListToGoSlice(cStatements), nil
+}
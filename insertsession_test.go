@@ -0,0 +1,145 @@
+package proj_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+const siteCRSWKT = `PROJCRS["ACME site grid",
+    BASEGEOGCRS["WGS 84",
+        DATUM["World Geodetic System 1984",
+            ELLIPSOID["WGS 84",6378137,298.257223563,
+                LENGTHUNIT["metre",1]]],
+        PRIMEM["Greenwich",0,
+            ANGLEUNIT["degree",0.0174532925199433]],
+        ID["EPSG",4326]],
+    CONVERSION["ACME site transverse mercator",
+        METHOD["Transverse Mercator",
+            ID["EPSG",9807]],
+        PARAMETER["Latitude of natural origin",47,
+            ANGLEUNIT["degree",0.0174532925199433],
+            ID["EPSG",8801]],
+        PARAMETER["Longitude of natural origin",8.5,
+            ANGLEUNIT["degree",0.0174532925199433],
+            ID["EPSG",8802]],
+        PARAMETER["Scale factor at natural origin",1,
+            SCALEUNIT["unity",1],
+            ID["EPSG",8805]],
+        PARAMETER["False easting",1000,
+            LENGTHUNIT["metre",1],
+            ID["EPSG",8806]],
+        PARAMETER["False northing",2000,
+            LENGTHUNIT["metre",1],
+            ID["EPSG",8807]]],
+    CS[Cartesian,2],
+        AXIS["easting (E)",east,
+            ORDER[1],
+            LENGTHUNIT["metre",1]],
+        AXIS["northing (N)",north,
+            ORDER[2],
+            LENGTHUNIT["metre",1]]]`
+
+func TestInsertSession_InsertStatements(t *testing.T) {
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	session, err := context.NewInsertSession()
+	if !proj.Capabilities().InsertStatements {
+		assert.IsError(t, err, proj.ErrUnsupported)
+		return
+	}
+	assert.NoError(t, err)
+	defer session.Destroy()
+
+	siteCRS, err := context.New(siteCRSWKT)
+	assert.NoError(t, err)
+
+	statements, err := session.InsertStatements(siteCRS, "ACME", "1001", true, nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, statements)
+	for _, statement := range statements {
+		assert.True(t, strings.HasPrefix(statement, "INSERT INTO"), statement)
+	}
+	assert.Contains(t, strings.Join(statements, "\n"), "'ACME','1001'")
+
+	session.Destroy()
+	_, err = session.InsertStatements(siteCRS, "ACME", "1002", true, nil)
+	assert.Error(t, err)
+}
+
+func TestInsertSession_auxiliaryDatabase(t *testing.T) {
+	if !proj.Capabilities().InsertStatements {
+		t.Skip("insert statements not supported")
+	}
+	sqlite3, err := exec.LookPath("sqlite3")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	structure, err := context.DatabaseStructure()
+	assert.NoError(t, err)
+
+	session, err := context.NewInsertSession()
+	assert.NoError(t, err)
+	defer session.Destroy()
+
+	siteCRS, err := context.New(siteCRSWKT)
+	assert.NoError(t, err)
+	statements, err := session.InsertStatements(siteCRS, "ACME", "1001", true, nil)
+	assert.NoError(t, err)
+
+	// Build the auxiliary database with the structure of the main database
+	// and the statements that insert the site CRS.
+	var sql strings.Builder
+	for _, statement := range append(structure, statements...) {
+		sql.WriteString(strings.TrimSuffix(strings.TrimSpace(statement), ";"))
+		sql.WriteString(";\n")
+	}
+	auxDBPath := filepath.Join(t.TempDir(), "aux.db")
+	cmd := exec.Command(sqlite3, "-bail", auxDBPath)
+	cmd.Stdin = strings.NewReader(sql.String())
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+
+	databasePath, err := context.DatabasePath()
+	assert.NoError(t, err)
+
+	auxContext := proj.NewContext()
+	assert.NoError(t, auxContext.SetDatabasePath(databasePath, []string{auxDBPath}))
+
+	pj, err := auxContext.NewCRSToCRS("EPSG:4326", "ACME:1001", nil)
+	assert.NoError(t, err)
+	actual, err := pj.Forward(proj.NewCoord(47, 8.5, 0, 0))
+	assert.NoError(t, err)
+	assertInDelta(t, 1000, actual.X(), 1e-6)
+	assertInDelta(t, 2000, actual.Y(), 1e-6)
+}
+
+func TestContext_DatabaseStructure(t *testing.T) {
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	statements, err := context.DatabaseStructure()
+	if !proj.Capabilities().InsertStatements {
+		assert.IsError(t, err, proj.ErrUnsupported)
+		return
+	}
+	assert.NoError(t, err)
+	assert.Contains(t, strings.Join(statements, "\n"), "CREATE TABLE")
+}
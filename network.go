@@ -0,0 +1,364 @@
+package proj
+
+// #include <stdlib.h>
+// #include "go-proj.h"
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"runtime/cgo"
+	"time"
+	"unsafe"
+)
+
+// A NetworkClient reads byte ranges of remote resources, such as grids on the
+// PROJ CDN, on behalf of PROJ.
+type NetworkClient interface {
+	// ReadRange reads up to len(p) bytes at offset of url into p. It returns
+	// the number of bytes read and the response headers, which must include
+	// Content-Range so that PROJ can determine the size of the resource.
+	ReadRange(url string, offset int64, p []byte) (int, http.Header, error)
+}
+
+// An HTTPNetworkClient is a NetworkClient that sends HTTP range requests.
+type HTTPNetworkClient struct {
+	Client *http.Client // Client sends the requests. If nil, http.DefaultClient is used.
+	Header http.Header  // Header is added to each request, for example for authentication.
+}
+
+// ReadRange implements NetworkClient.
+func (c *HTTPNetworkClient) ReadRange(url string, offset int64, p []byte) (int, http.Header, error) {
+	if len(p) == 0 {
+		return 0, nil, nil
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	for key, values := range c.Header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+int64(len(p))-1))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusPartialContent {
+		return 0, nil, fmt.Errorf("%s: %s", url, response.Status)
+	}
+
+	n, err := io.ReadFull(response.Body, p)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, nil, err
+	}
+	return n, response.Header, nil
+}
+
+// EnableNetwork sets whether PROJ may access remote resources. Enabling it
+// fails if PROJ was built without network support and no NetworkClient was
+// set with SetNetworkCallbacks.
+func (c *Context) EnableNetwork(enable bool) error {
+	if !Capabilities().Network {
+		return ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	var cEnable C.int
+	if enable {
+		cEnable = 1
+	}
+	if C.proj_context_set_enable_network(c.pjContext, cEnable) != cEnable {
+		return fmt.Errorf("network access not available")
+	}
+	return nil
+}
+
+// IsNetworkEnabled returns whether PROJ may access remote resources.
+func (c *Context) IsNetworkEnabled() bool {
+	c.Lock()
+	defer c.Unlock()
+	return C.proj_context_is_network_enabled(c.pjContext) != 0
+}
+
+// SetURLEndpoint sets the URL from which PROJ fetches resources that are not
+// found locally. The default is https://cdn.proj.org.
+func (c *Context) SetURLEndpoint(url string) error {
+	if !Capabilities().Network {
+		return ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	cURL := C.CString(url)
+	defer C.free(unsafe.Pointer(cURL))
+
+	C.proj_context_set_url_endpoint(c.pjContext, cURL)
+	return nil
+}
+
+// URLEndpoint returns the URL from which PROJ fetches resources that are not
+// found locally.
+func (c *Context) URLEndpoint() string {
+	c.Lock()
+	defer c.Unlock()
+	return C.GoString(C.proj_context_get_url_endpoint(c.pjContext))
+}
+
+// SetCABundlePath sets the path to the certificate authority bundle used by
+// PROJ's built-in network access.
+func (c *Context) SetCABundlePath(path string) error {
+	if !Capabilities().CABundlePath {
+		return ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	C.proj_context_set_ca_bundle_path(c.pjContext, cPath)
+	return nil
+}
+
+// SetNetworkCallbacks routes all network access by PROJ through client
+// instead of PROJ's built-in network access. client is called while c is
+// locked, so it must not call any methods on c or on PJs created from c.
+// Network access must still be enabled with EnableNetwork.
+func (c *Context) SetNetworkCallbacks(client NetworkClient) error {
+	if !Capabilities().Network {
+		return ErrUnsupported
+	}
+	if client == nil {
+		return fmt.Errorf("nil network client")
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	networkHandle := cgo.NewHandle(client)
+	if C.go_proj_set_network_callbacks(c.pjContext, C.uintptr_t(networkHandle)) == 0 {
+		networkHandle.Delete()
+		return fmt.Errorf("cannot set network callbacks")
+	}
+	if c.networkHandle != 0 {
+		c.networkHandle.Delete()
+	}
+	c.networkHandle = networkHandle
+	return nil
+}
+
+// EnableGridCache sets whether chunks of remote grids are cached on disk. The
+// cache is enabled by default.
+func (c *Context) EnableGridCache(enable bool) error {
+	if !Capabilities().GridCache {
+		return ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	var cEnable C.int
+	if enable {
+		cEnable = 1
+	}
+	C.proj_grid_cache_set_enable(c.pjContext, cEnable)
+	return nil
+}
+
+// SetGridCacheFilename sets the path of the SQLite database used to cache
+// chunks of remote grids. The default is cache.db in the user writable
+// directory. Processes may share the same cache.
+func (c *Context) SetGridCacheFilename(path string) error {
+	if !Capabilities().GridCache {
+		return ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	C.proj_grid_cache_set_filename(c.pjContext, cPath)
+	return nil
+}
+
+// SetGridCacheMaxSize sets the maximum size of the grid cache in megabytes.
+// The default is 300 MB. A negative size means that the size is unlimited.
+func (c *Context) SetGridCacheMaxSize(maxSizeMB int) error {
+	if !Capabilities().GridCache {
+		return ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	C.proj_grid_cache_set_max_size(c.pjContext, C.int(maxSizeMB))
+	return nil
+}
+
+// SetGridCacheTTL sets how long cached chunks are used before PROJ checks
+// whether the remote grid has changed. The default is one day. ttl is rounded
+// down to whole seconds and clamped to the range of a C int.
+func (c *Context) SetGridCacheTTL(ttl time.Duration) error {
+	if !Capabilities().GridCache {
+		return ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	seconds := min(max(int64(ttl/time.Second), math.MinInt32), math.MaxInt32)
+	C.proj_grid_cache_set_ttl(c.pjContext, C.int(seconds))
+	return nil
+}
+
+// ClearGridCache removes all cached chunks of remote grids.
+func (c *Context) ClearGridCache() error {
+	if !Capabilities().GridCache {
+		return ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	C.proj_grid_cache_clear(c.pjContext)
+	return nil
+}
+
+// EnableNetwork sets whether PROJ may access remote resources for the default
+// context.
+func EnableNetwork(enable bool) error {
+	return defaultContext.EnableNetwork(enable)
+}
+
+// SetURLEndpoint sets the URL from which PROJ fetches resources for the
+// default context.
+func SetURLEndpoint(url string) error {
+	return defaultContext.SetURLEndpoint(url)
+}
+
+// SetCABundlePath sets the path to the certificate authority bundle for the
+// default context.
+func SetCABundlePath(path string) error {
+	return defaultContext.SetCABundlePath(path)
+}
+
+// SetNetworkCallbacks routes all network access by PROJ for the default
+// context through client.
+func SetNetworkCallbacks(client NetworkClient) error {
+	return defaultContext.SetNetworkCallbacks(client)
+}
+
+// A networkFile is a remote resource opened by PROJ.
+type networkFile struct {
+	client        NetworkClient
+	url           string
+	header        http.Header
+	cHeaderValues []*C.char
+}
+
+// readRange reads into p at offset and keeps the response headers.
+func (f *networkFile) readRange(offset int64, p []byte) (int, error) {
+	n, header, err := f.client.ReadRange(f.url, offset, p)
+	if err != nil {
+		return 0, err
+	}
+	f.header = header
+	return n, nil
+}
+
+// setCErrorString copies the message of err into the C buffer cStr of size
+// bytes, truncating it if needed.
+func setCErrorString(cStr *C.char, size C.size_t, err error) {
+	if cStr == nil || size == 0 {
+		return
+	}
+	buffer := unsafe.Slice((*byte)(unsafe.Pointer(cStr)), size)
+	n := copy(buffer[:size-1], err.Error())
+	buffer[n] = 0
+}
+
+//export goProjNetworkOpen
+func goProjNetworkOpen(clientHandle C.uintptr_t, cURL *C.char, offset C.ulonglong, sizeToRead C.size_t, buffer unsafe.Pointer, outSizeRead *C.size_t, errorStringMaxSize C.size_t, outErrorString *C.char) C.uintptr_t {
+	client, ok := cgo.Handle(clientHandle).Value().(NetworkClient)
+	if !ok {
+		return 0
+	}
+
+	f := &networkFile{
+		client: client,
+		url:    C.GoString(cURL),
+	}
+	n, err := f.readRange(int64(offset), unsafe.Slice((*byte)(buffer), sizeToRead))
+	if err != nil {
+		setCErrorString(outErrorString, errorStringMaxSize, err)
+		return 0
+	}
+	*outSizeRead = C.size_t(n)
+	return C.uintptr_t(cgo.NewHandle(f))
+}
+
+//export goProjNetworkClose
+func goProjNetworkClose(fileHandle C.uintptr_t) {
+	handle := cgo.Handle(fileHandle)
+	if f, ok := handle.Value().(*networkFile); ok {
+		for _, cHeaderValue := range f.cHeaderValues {
+			C.free(unsafe.Pointer(cHeaderValue))
+		}
+	}
+	handle.Delete()
+}
+
+//export goProjNetworkGetHeaderValue
+func goProjNetworkGetHeaderValue(fileHandle C.uintptr_t, cHeaderName *C.char) *C.char {
+	f, ok := cgo.Handle(fileHandle).Value().(*networkFile)
+	if !ok {
+		return nil
+	}
+
+	values := f.header.Values(C.GoString(cHeaderName))
+	if len(values) == 0 {
+		return nil
+	}
+
+	// The value must remain valid until the file is closed.
+	cHeaderValue := C.CString(values[0])
+	f.cHeaderValues = append(f.cHeaderValues, cHeaderValue)
+	return cHeaderValue
+}
+
+//export goProjNetworkReadRange
+func goProjNetworkReadRange(fileHandle C.uintptr_t, offset C.ulonglong, sizeToRead C.size_t, buffer unsafe.Pointer, errorStringMaxSize C.size_t, outErrorString *C.char) C.size_t {
+	f, ok := cgo.Handle(fileHandle).Value().(*networkFile)
+	if !ok {
+		return 0
+	}
+
+	n, err := f.readRange(int64(offset), unsafe.Slice((*byte)(buffer), sizeToRead))
+	if err != nil {
+		setCErrorString(outErrorString, errorStringMaxSize, err)
+		return 0
+	}
+	return C.size_t(n)
+}
@@ -0,0 +1,155 @@
+package proj_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+// A testGridServer is an HTTP server that serves grid files and records the
+// requests that it receives.
+type testGridServer struct {
+	*httptest.Server
+	grids    map[string][]byte
+	mutex    sync.Mutex
+	requests []*http.Request
+}
+
+func newTestGridServer(tb testing.TB, grids map[string][]byte) *testGridServer {
+	tb.Helper()
+	s := &testGridServer{
+		grids: grids,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		s.requests = append(s.requests, r)
+		s.mutex.Unlock()
+
+		data, ok := grids[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(data))
+	}))
+	tb.Cleanup(s.Close)
+	return s
+}
+
+func (s *testGridServer) Requests() []*http.Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+func TestHTTPNetworkClient_ReadRange(t *testing.T) {
+	server := newTestGridServer(t, map[string][]byte{
+		"grid.tif": []byte("0123456789"),
+	})
+
+	client := &proj.HTTPNetworkClient{
+		Header: http.Header{
+			"Authorization": []string{"Bearer secret"},
+		},
+	}
+
+	p := make([]byte, 4)
+	n, header, err := client.ReadRange(server.URL+"/grid.tif", 2, p)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, "2345", string(p[:n]))
+	assert.Equal(t, "bytes 2-5/10", header.Get("Content-Range"))
+
+	p = make([]byte, 16)
+	n, _, err = client.ReadRange(server.URL+"/grid.tif", 6, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "6789", string(p[:n]))
+
+	_, _, err = client.ReadRange(server.URL+"/missing.tif", 0, p)
+	assert.Error(t, err)
+
+	requests := len(server.Requests())
+	n, _, err = client.ReadRange(server.URL+"/grid.tif", 2, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, requests, len(server.Requests()))
+
+	for _, request := range server.Requests() {
+		assert.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+	}
+}
+
+func TestContext_SetNetworkCallbacks(t *testing.T) {
+	if !proj.Capabilities().Network {
+		t.Skip("network access not supported")
+	}
+
+	defer runtime.GC()
+
+	server := newTestGridServer(t, map[string][]byte{
+		"go_proj_invalid_grid.tif": []byte("not a GeoTIFF file"),
+	})
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	assert.NoError(t, context.SetNetworkCallbacks(&proj.HTTPNetworkClient{}))
+	assert.NoError(t, context.EnableNetwork(true))
+	assert.True(t, context.IsNetworkEnabled())
+	assert.NoError(t, context.SetURLEndpoint(server.URL))
+	assert.Equal(t, server.URL, context.URLEndpoint())
+
+	// The grid is fetched through the Go client, but is not a valid GeoTIFF.
+	_, err := context.New("+proj=vgridshift +grids=" + server.URL + "/go_proj_invalid_grid.tif +multiplier=1")
+	assert.Error(t, err)
+
+	requests := server.Requests()
+	assert.NotZero(t, requests)
+	assert.Equal(t, "/go_proj_invalid_grid.tif", requests[0].URL.Path)
+	assert.True(t, strings.HasPrefix(requests[0].Header.Get("Range"), "bytes=0-"))
+
+	assert.NoError(t, context.EnableNetwork(false))
+	assert.False(t, context.IsNetworkEnabled())
+}
+
+func TestContext_GridCache(t *testing.T) {
+	if !proj.Capabilities().GridCache {
+		t.Skip("grid cache not supported")
+	}
+
+	defer runtime.GC()
+
+	server := newTestGridServer(t, map[string][]byte{
+		"go_proj_invalid_grid.tif": []byte("not a GeoTIFF file"),
+	})
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	cacheFilename := filepath.Join(t.TempDir(), "cache.db")
+	assert.NoError(t, context.EnableGridCache(true))
+	assert.NoError(t, context.SetGridCacheFilename(cacheFilename))
+	assert.NoError(t, context.SetGridCacheMaxSize(1))
+	assert.NoError(t, context.SetGridCacheTTL(time.Hour))
+	assert.NoError(t, context.SetNetworkCallbacks(&proj.HTTPNetworkClient{}))
+	assert.NoError(t, context.EnableNetwork(true))
+
+	_, err := context.New("+proj=vgridshift +grids=" + server.URL + "/go_proj_invalid_grid.tif +multiplier=1")
+	assert.Error(t, err)
+
+	_, err = os.Stat(cacheFilename)
+	assert.NoError(t, err)
+	assert.NoError(t, context.ClearGridCache())
+	assert.NoError(t, context.EnableGridCache(false))
+}
@@ -5,6 +5,7 @@ import "C"
 
 import (
 	"fmt"
+	"math"
 	"unsafe"
 )
 
@@ -20,8 +21,9 @@ const (
 
 // A PJ is a projection or a transformation.
 type PJ struct {
-	context *Context
-	pj      *C.PJ
+	context        *Context
+	pj             *C.PJ
+	normalizeUnits bool
 }
 
 // A PJInfo contains information about a PJ.
@@ -60,6 +62,33 @@ type IdentifyMatchInfo struct {
 	Confidence  int
 }
 
+// A GeodResult is the solution of the inverse geodesic problem between two
+// points, as returned by Geod.
+type GeodResult struct {
+	Distance       float64
+	ForwardAzimuth float64
+	ReverseAzimuth float64
+}
+
+// A RoundTripReport is the drift of a round trip of each of a set of
+// coordinates, as returned by RoundTripReport.
+type RoundTripReport struct {
+	Drifts   []float64 // Drift of each coord, or +Inf if its round trip failed.
+	MaxDrift float64   // Largest drift.
+	MaxIndex int       // Index of the coord with the largest drift.
+}
+
+// A GridUsed describes a grid used by a coordinate operation.
+type GridUsed struct {
+	ShortName      string // File name of the grid, e.g. "us_noaa_conus.tif".
+	FullName       string // Full path of the grid if it is available locally.
+	PackageName    string // Name of the package containing the grid, if any.
+	URL            string // URL from which the grid or its package can be downloaded.
+	DirectDownload bool   // True if URL points directly to the grid rather than to a package.
+	OpenLicense    bool   // True if the grid is released under an open license.
+	Available      bool   // True if the grid is available locally.
+}
+
 // A match from the Identify() method. For the meanings of confidence consult
 // https://proj.org/en/stable/development/reference/functions.html#c.proj_identify
 type IdentifyMatch struct {
@@ -67,6 +96,46 @@ type IdentifyMatch struct {
 	Confidence int
 }
 
+// AngularInput returns whether pj expects angular input coordinates, in
+// radians, in direction.
+func (pj *PJ) AngularInput(direction Direction) bool {
+	pj.context.Lock()
+	defer pj.context.Unlock()
+	return C.proj_angular_input(pj.pj, (C.enum_PJ_DIRECTION)(direction)) != 0
+}
+
+// AngularOutput returns whether pj returns angular output coordinates, in
+// radians, in direction.
+func (pj *PJ) AngularOutput(direction Direction) bool {
+	pj.context.Lock()
+	defer pj.context.Unlock()
+	return C.proj_angular_output(pj.pj, (C.enum_PJ_DIRECTION)(direction)) != 0
+}
+
+// DegreeInput returns whether pj expects input coordinates in degrees in
+// direction.
+func (pj *PJ) DegreeInput(direction Direction) (bool, error) {
+	if !Capabilities().DegreeUnits {
+		return false, ErrUnsupported
+	}
+
+	pj.context.Lock()
+	defer pj.context.Unlock()
+	return C.proj_degree_input(pj.pj, (C.enum_PJ_DIRECTION)(direction)) != 0, nil
+}
+
+// DegreeOutput returns whether pj returns output coordinates in degrees in
+// direction.
+func (pj *PJ) DegreeOutput(direction Direction) (bool, error) {
+	if !Capabilities().DegreeUnits {
+		return false, ErrUnsupported
+	}
+
+	pj.context.Lock()
+	defer pj.context.Unlock()
+	return C.proj_degree_output(pj.pj, (C.enum_PJ_DIRECTION)(direction)) != 0, nil
+}
+
 // Destroy releases all resources associated with pj.
 func (pj *PJ) Destroy() {
 	pj.context.Lock()
@@ -89,6 +158,27 @@ func (pj *PJ) NormalizeForVisualization() (*PJ, error) {
 	return pj.context.newPJ(C.proj_normalize_for_visualization(pj.context.pjContext, pj.pj))
 }
 
+// NormalizeUnits returns whether the Trans methods of pj take and return
+// angular coordinates in degrees.
+func (pj *PJ) NormalizeUnits() bool {
+	pj.context.Lock()
+	defer pj.context.Unlock()
+	return pj.normalizeUnits
+}
+
+// SetNormalizeUnits sets whether the Trans methods of pj, and the Forward and
+// Inverse methods built on them, take and return angular coordinates in
+// degrees. If set, longitudes and latitudes are converted from degrees to
+// radians where pj expects radians, and back to degrees where pj returns
+// radians, so callers do not need to call Coord.DegToRad and Coord.RadToDeg.
+// Coordinates that pj already expects or returns in degrees are not
+// converted. Other methods, such as Factors and Geod, are not affected.
+func (pj *PJ) SetNormalizeUnits(normalizeUnits bool) {
+	pj.context.Lock()
+	defer pj.context.Unlock()
+	pj.normalizeUnits = normalizeUnits
+}
+
 // Forward transforms coord in the forward direction.
 func (pj *PJ) Forward(coord Coord) (Coord, error) {
 	return pj.Trans(DirectionFwd, coord)
@@ -128,13 +218,161 @@ func (pj *PJ) Geod(a, b Coord) (float64, float64, float64) {
 	return (float64)(cGeod.s), (float64)(cGeod.a1), (float64)(cGeod.a2)
 }
 
+// GeodArray computes Geod for each pair of a and b into out, in a single call
+// to PROJ. a and b must have the same length, and out must be at least as
+// long.
+func (pj *PJ) GeodArray(a, b []Coord, out []GeodResult) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("%d coords and %d coords", len(a), len(b))
+	}
+	if len(out) < len(a) {
+		return fmt.Errorf("%d results for %d coords", len(out), len(a))
+	}
+	if len(a) == 0 {
+		return nil
+	}
+
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	C.go_proj_geod_array(pj.pj, (C.size_t)(len(a)),
+		(*C.PJ_COORD)(unsafe.Pointer(&a[0])), (*C.PJ_COORD)(unsafe.Pointer(&b[0])),
+		(*C.double)(unsafe.Pointer(&out[0])))
+	return nil
+}
+
+// PathLength returns the geodesic length of the path through coords in
+// geodetic coordinates, as the sum of LPDist between consecutive coords, in a
+// single call to PROJ.
+func (pj *PJ) PathLength(coords []Coord) float64 {
+	if len(coords) < 2 {
+		return 0
+	}
+
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	return (float64)(C.go_proj_path_length(pj.pj, (C.size_t)(len(coords)), (*C.PJ_COORD)(unsafe.Pointer(&coords[0]))))
+}
+
+// DistanceMatrix returns the LPDist between each pair of points in geodetic
+// coordinates, in a single call to PROJ. The returned rows share a single
+// allocation.
+func (pj *PJ) DistanceMatrix(points []Coord) [][]float64 {
+	n := len(points)
+	if n == 0 {
+		return nil
+	}
+
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	distances := make([]float64, n*n)
+	C.go_proj_distance_matrix(pj.pj, (C.size_t)(n), (*C.PJ_COORD)(unsafe.Pointer(&points[0])), (*C.double)(unsafe.Pointer(&distances[0])))
+
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = distances[i*n : (i+1)*n : (i+1)*n]
+	}
+	return matrix
+}
+
+// GeodesicPolygonArea returns the signed area, in square meters, and the
+// perimeter of the polygon with vertices points on pj's ellipsoid. The X and Y
+// of each point are its longitude and latitude in radians, as for Geod. See
+// Geodesic.PolygonArea and NewGeodesicFromPJ.
+func (pj *PJ) GeodesicPolygonArea(points []Coord) (float64, float64, error) {
+	geodesic, err := NewGeodesicFromPJ(pj)
+	if err != nil {
+		return 0, 0, err
+	}
+	area, perimeter := geodesic.PolygonArea(coordsRadToDeg(points))
+	return area, perimeter, nil
+}
+
+// GeodesicCircle returns a closed, counter-clockwise ring of segments points
+// at distance radius from center on pj's ellipsoid. Longitudes and latitudes
+// are in radians, as for Geod, and in that order. PJs from NewCRSToCRS take
+// degrees, so the ring must be converted with Coord.RadToDeg before it is
+// transformed with TransArray, and its axes swapped for CRSs with latitude
+// first unless the PJ is normalized with NormalizeForVisualization. See
+// Geodesic.Circle.
+func (pj *PJ) GeodesicCircle(center Coord, radius float64, segments int) ([]Coord, error) {
+	geodesic, err := NewGeodesicFromPJ(pj)
+	if err != nil {
+		return nil, err
+	}
+	ring, err := geodesic.Circle(center.RadToDeg(), radius, segments)
+	if err != nil {
+		return nil, err
+	}
+	return coordsDegToRad(ring), nil
+}
+
+// GeodesicCorridor returns a closed, counter-clockwise ring that approximates
+// all points within halfWidth of line on pj's ellipsoid. Longitudes and
+// latitudes are in radians, as for Geod, and in that order. As for
+// GeodesicCircle, the ring must be converted to degrees before it is
+// transformed with TransArray by a PJ from NewCRSToCRS. See Geodesic.Corridor.
+func (pj *PJ) GeodesicCorridor(line []Coord, halfWidth float64) ([]Coord, error) {
+	geodesic, err := NewGeodesicFromPJ(pj)
+	if err != nil {
+		return nil, err
+	}
+	ring, err := geodesic.Corridor(coordsRadToDeg(line), halfWidth)
+	if err != nil {
+		return nil, err
+	}
+	return coordsDegToRad(ring), nil
+}
+
 // GetLastUsedOperation returns the operation used in the last call to Trans.
 func (pj *PJ) GetLastUsedOperation() (*PJ, error) {
+	if !Capabilities().LastUsedOperation {
+		return nil, ErrUnsupported
+	}
+
 	pj.context.Lock()
 	defer pj.context.Unlock()
 	return pj.context.newPJ(C.proj_trans_get_last_used_operation(pj.pj))
 }
 
+// GridsUsed returns the grids used by the coordinate operation pj, including
+// the grids of all steps of a concatenated operation.
+func (pj *PJ) GridsUsed() ([]GridUsed, error) {
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	lastErrno := C.proj_errno_reset(pj.pj)
+	defer C.proj_errno_restore(pj.pj, lastErrno)
+
+	count := int(C.proj_coordoperation_get_grid_used_count(pj.context.pjContext, pj.pj))
+	if errno := int(C.proj_errno(pj.pj)); errno != 0 {
+		return nil, pj.context.newError(errno)
+	}
+
+	grids := make([]GridUsed, 0, count)
+	for i := 0; i < count; i++ {
+		var shortName, fullName, packageName, url *C.char
+		var directDownload, openLicense, available C.int
+		if C.proj_coordoperation_get_grid_used(pj.context.pjContext, pj.pj, C.int(i),
+			&shortName, &fullName, &packageName, &url,
+			&directDownload, &openLicense, &available) == 0 {
+			return nil, pj.context.newError(int(C.proj_errno(pj.pj)))
+		}
+		grids = append(grids, GridUsed{
+			ShortName:      C.GoString(shortName),
+			FullName:       C.GoString(fullName),
+			PackageName:    C.GoString(packageName),
+			URL:            C.GoString(url),
+			DirectDownload: directDownload != 0,
+			OpenLicense:    openLicense != 0,
+			Available:      available != 0,
+		})
+	}
+	return grids, nil
+}
+
 // Info returns information about pj.
 func (pj *PJ) Info() PJInfo {
 	pj.context.Lock()
@@ -800,6 +1038,78 @@ func (pj *PJ) LPZDist(a, b Coord) float64 {
 	return (float64)(C.proj_lpz_dist(pj.pj, *(*C.PJ_COORD)(unsafe.Pointer(&a)), *(*C.PJ_COORD)(unsafe.Pointer(&b))))
 }
 
+// RoundTrip transforms coord n times back and forth, starting in direction,
+// and returns the distance between coord and the result. The distance is in
+// meters if the input of pj in direction is angular, and in the units of the
+// input otherwise.
+func (pj *PJ) RoundTrip(direction Direction, n int, coord Coord) (float64, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("%d: invalid number of round trips", n)
+	}
+
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	lastErrno := C.proj_errno_reset(pj.pj)
+	defer C.proj_errno_restore(pj.pj, lastErrno)
+
+	distance := C.proj_roundtrip(pj.pj, (C.PJ_DIRECTION)(direction), (C.int)(n), (*C.PJ_COORD)(unsafe.Pointer(&coord)))
+	if errno := int(C.proj_errno(pj.pj)); errno != 0 {
+		return 0, pj.context.newError(errno)
+	}
+	return (float64)(distance), nil
+}
+
+// RoundTripReport returns the drift of n round trips of each of coords,
+// starting in direction, as returned by RoundTrip, in a single call to PROJ. If
+// the round trip of any coord fails then its drift is +Inf and the error of the
+// first such coord is returned with the report.
+func (pj *PJ) RoundTripReport(direction Direction, n int, coords []Coord) (*RoundTripReport, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%d: invalid number of round trips", n)
+	}
+
+	report := &RoundTripReport{
+		Drifts: make([]float64, len(coords)),
+	}
+	if len(coords) == 0 {
+		return report, nil
+	}
+
+	errnos := pj.roundTripArray(direction, n, coords, report.Drifts)
+
+	var err error
+	for i, errno := range errnos {
+		if errno != 0 {
+			report.Drifts[i] = math.Inf(1)
+			if err == nil {
+				err = fmt.Errorf("coord %d: %w", i, pj.context.newError(int(errno)))
+			}
+		}
+		if report.Drifts[i] > report.MaxDrift {
+			report.MaxDrift = report.Drifts[i]
+			report.MaxIndex = i
+		}
+	}
+	return report, err
+}
+
+// roundTripArray computes the drifts of n round trips of coords and returns the errno
+// of each coord. Errors are wrapped by the caller, as formatting an Error locks
+// the context.
+func (pj *PJ) roundTripArray(direction Direction, n int, coords []Coord, drifts []float64) []C.int {
+	pj.context.Lock()
+	defer pj.context.Unlock()
+
+	lastErrno := C.proj_errno_reset(pj.pj)
+	defer C.proj_errno_restore(pj.pj, lastErrno)
+
+	errnos := make([]C.int, len(coords))
+	C.go_proj_roundtrip_array(pj.pj, (C.PJ_DIRECTION)(direction), (C.int)(n), (C.size_t)(len(coords)),
+		(*C.PJ_COORD)(unsafe.Pointer(&coords[0])), (*C.double)(unsafe.Pointer(&drifts[0])), &errnos[0])
+	return errnos
+}
+
 // Trans transforms a single Coord in place.
 func (pj *PJ) Trans(direction Direction, coord Coord) (Coord, error) {
 	pj.context.Lock()
@@ -808,11 +1118,18 @@ func (pj *PJ) Trans(direction Direction, coord Coord) (Coord, error) {
 	lastErrno := C.proj_errno_reset(pj.pj)
 	defer C.proj_errno_restore(pj.pj, lastErrno)
 
+	inScale, outScale := pj.unitScales(direction)
+	coord[0] *= inScale
+	coord[1] *= inScale
+
 	pjCoord := C.proj_trans(pj.pj, (C.PJ_DIRECTION)(direction), *(*C.PJ_COORD)(unsafe.Pointer(&coord)))
 	if errno := int(C.proj_errno(pj.pj)); errno != 0 {
 		return Coord{}, pj.context.newError(errno)
 	}
-	return *(*Coord)(unsafe.Pointer(&pjCoord)), nil
+	transCoord := *(*Coord)(unsafe.Pointer(&pjCoord))
+	transCoord[0] *= outScale
+	transCoord[1] *= outScale
+	return transCoord, nil
 }
 
 // TransArray transforms an array of Coords.
@@ -827,17 +1144,34 @@ func (pj *PJ) TransArray(direction Direction, coords []Coord) error {
 	lastErrno := C.proj_errno_reset(pj.pj)
 	defer C.proj_errno_restore(pj.pj, lastErrno)
 
-	if errno := int(C.proj_trans_array(pj.pj, (C.PJ_DIRECTION)(direction), (C.size_t)(len(coords)), (*C.PJ_COORD)(unsafe.Pointer(&coords[0])))); errno != 0 {
+	inScale, outScale := pj.unitScales(direction)
+	scaleCoords(coords, inScale)
+	errno := int(C.proj_trans_array(pj.pj, (C.PJ_DIRECTION)(direction), (C.size_t)(len(coords)), (*C.PJ_COORD)(unsafe.Pointer(&coords[0]))))
+	scaleCoords(coords, outScale)
+	if errno != 0 {
 		return pj.context.newError(errno)
 	}
 	return nil
 }
 
-// TransBounds transforms bounds.
+// TransBounds transforms bounds. densifyPoints is the number of points to add
+// to each edge of bounds to account for non-linear edges. If the PROJ version
+// does not support proj_trans_bounds then the densified edges are transformed
+// in Go, without special handling of the antimeridian.
 func (pj *PJ) TransBounds(direction Direction, bounds Bounds, densifyPoints int) (Bounds, error) {
+	if !Capabilities().TransBounds {
+		return pj.transBoundsDensified(direction, bounds, densifyPoints)
+	}
+
 	pj.context.Lock()
 	defer pj.context.Unlock()
 
+	inScale, outScale := pj.unitScales(direction)
+	bounds.XMin *= inScale
+	bounds.YMin *= inScale
+	bounds.XMax *= inScale
+	bounds.YMax *= inScale
+
 	var transBounds Bounds
 	if C.proj_trans_bounds(pj.context.pjContext, pj.pj, (C.PJ_DIRECTION)(direction),
 		(C.double)(bounds.XMin), (C.double)(bounds.YMin), (C.double)(bounds.XMax), (C.double)(bounds.YMax),
@@ -845,6 +1179,54 @@ func (pj *PJ) TransBounds(direction Direction, bounds Bounds, densifyPoints int)
 		C.int(densifyPoints)) == 0 {
 		return Bounds{}, pj.context.newError(int(C.proj_errno(pj.pj)))
 	}
+	transBounds.XMin *= outScale
+	transBounds.YMin *= outScale
+	transBounds.XMax *= outScale
+	transBounds.YMax *= outScale
+	return transBounds, nil
+}
+
+// transBoundsDensified transforms bounds by transforming densifyPoints points
+// along each edge of bounds and returning the bounds of the transformed points.
+func (pj *PJ) transBoundsDensified(direction Direction, bounds Bounds, densifyPoints int) (Bounds, error) {
+	segments := max(densifyPoints, 0) + 1
+	dx := (bounds.XMax - bounds.XMin) / float64(segments)
+	dy := (bounds.YMax - bounds.YMin) / float64(segments)
+	coords := make([]Coord, 0, 4*segments)
+	for i := 0; i < segments; i++ {
+		coords = append(coords,
+			Coord{bounds.XMin + float64(i)*dx, bounds.YMin},
+			Coord{bounds.XMax, bounds.YMin + float64(i)*dy},
+			Coord{bounds.XMax - float64(i)*dx, bounds.YMax},
+			Coord{bounds.XMin, bounds.YMax - float64(i)*dy},
+		)
+	}
+
+	// Points that fail to transform are set to HUGE_VAL and are skipped, so
+	// the error is only returned if no point could be transformed.
+	transErr := pj.TransArray(direction, coords)
+
+	transBounds := Bounds{
+		XMin: math.Inf(1),
+		YMin: math.Inf(1),
+		XMax: math.Inf(-1),
+		YMax: math.Inf(-1),
+	}
+	for _, coord := range coords {
+		if math.IsInf(coord[0], 0) || math.IsInf(coord[1], 0) || math.IsNaN(coord[0]) || math.IsNaN(coord[1]) {
+			continue
+		}
+		transBounds.XMin = min(transBounds.XMin, coord[0])
+		transBounds.YMin = min(transBounds.YMin, coord[1])
+		transBounds.XMax = max(transBounds.XMax, coord[0])
+		transBounds.YMax = max(transBounds.YMax, coord[1])
+	}
+	if math.IsInf(transBounds.XMin, 1) {
+		if transErr == nil {
+			transErr = fmt.Errorf("no point of bounds could be transformed")
+		}
+		return Bounds{}, transErr
+	}
 	return transBounds, nil
 }
 
@@ -913,14 +1295,58 @@ func (pj *PJ) TransGeneric(direction Direction, x *float64, sx, nx int, y *float
 	lastErrno := C.proj_errno_reset(pj.pj)
 	defer C.proj_errno_restore(pj.pj, lastErrno)
 
-	if int(C.proj_trans_generic(pj.pj, (C.PJ_DIRECTION)(direction),
+	inScale, outScale := pj.unitScales(direction)
+	scaleGeneric(x, sx, nx, inScale)
+	scaleGeneric(y, sy, ny, inScale)
+	n := int(C.proj_trans_generic(pj.pj, (C.PJ_DIRECTION)(direction),
 		(*C.double)(x), C.size_t(sx), C.size_t(nx),
 		(*C.double)(y), C.size_t(sy), C.size_t(ny),
 		(*C.double)(z), C.size_t(sz), C.size_t(nz),
 		(*C.double)(m), C.size_t(sm), C.size_t(nm),
-	)) != max(nx, ny, nz, nm) {
+	))
+	scaleGeneric(x, sx, nx, outScale)
+	scaleGeneric(y, sy, ny, outScale)
+	if n != max(nx, ny, nz, nm) {
 		return pj.context.newError(int(C.proj_errno(pj.pj)))
 	}
 
 	return nil
 }
+
+// unitScales returns the factors by which the horizontal input and output
+// coordinates of pj in direction are multiplied to normalize units. The
+// context of pj must be locked.
+func (pj *PJ) unitScales(direction Direction) (float64, float64) {
+	inScale, outScale := 1.0, 1.0
+	if !pj.normalizeUnits {
+		return inScale, outScale
+	}
+	if C.proj_angular_input(pj.pj, (C.enum_PJ_DIRECTION)(direction)) != 0 {
+		inScale = math.Pi / 180
+	}
+	if C.proj_angular_output(pj.pj, (C.enum_PJ_DIRECTION)(direction)) != 0 {
+		outScale = 180 / math.Pi
+	}
+	return inScale, outScale
+}
+
+// scaleCoords multiplies the horizontal coordinates of coords by scale.
+func scaleCoords(coords []Coord, scale float64) {
+	if scale == 1 {
+		return
+	}
+	for i := range coords {
+		coords[i][0] *= scale
+		coords[i][1] *= scale
+	}
+}
+
+// scaleGeneric multiplies the n values at p, stride bytes apart, by scale.
+func scaleGeneric(p *float64, stride, n int, scale float64) {
+	if p == nil || scale == 1 {
+		return
+	}
+	for i := 0; i < n; i++ {
+		*(*float64)(unsafe.Add(unsafe.Pointer(p), i*stride)) *= scale
+	}
+}
@@ -114,6 +114,211 @@ func TestPJ_LPDist(t *testing.T) {
 	}
 }
 
+func TestPJ_GeodArray(t *testing.T) {
+	if proj.VersionMajor < 7 {
+		t.Skip("distance functions not tested")
+	}
+
+	defer runtime.GC()
+
+	pj, err := proj.New("epsg:4326")
+	assert.NoError(t, err)
+
+	a := []proj.Coord{bernEPSG4326.DegToRad(), newYorkEPSG4326.DegToRad()}
+	b := []proj.Coord{zurichEPSG4326.DegToRad(), parisEPSG4326.DegToRad()}
+	out := make([]proj.GeodResult, len(a))
+	assert.NoError(t, pj.GeodArray(a, b, out))
+	for i := range a {
+		expectedDistance, expectedForwardAzimuth, expectedReverseAzimuth := pj.Geod(a[i], b[i])
+		assert.Equal(t, proj.GeodResult{
+			Distance:       expectedDistance,
+			ForwardAzimuth: expectedForwardAzimuth,
+			ReverseAzimuth: expectedReverseAzimuth,
+		}, out[i])
+	}
+
+	assert.Error(t, pj.GeodArray(a, b[:1], out))
+	assert.Error(t, pj.GeodArray(a, b, out[:1]))
+	assert.NoError(t, pj.GeodArray(nil, nil, nil))
+}
+
+func TestPJ_PathLength(t *testing.T) {
+	if proj.VersionMajor < 7 {
+		t.Skip("distance functions not tested")
+	}
+
+	defer runtime.GC()
+
+	pj, err := proj.New("epsg:4326")
+	assert.NoError(t, err)
+
+	path := []proj.Coord{bernEPSG4326.DegToRad(), zurichEPSG4326.DegToRad(), parisEPSG4326.DegToRad()}
+	expected := pj.LPDist(path[0], path[1]) + pj.LPDist(path[1], path[2])
+	assertInDelta(t, expected, pj.PathLength(path), 1e-6)
+	assert.Equal(t, 0., pj.PathLength(path[:1]))
+	assert.Equal(t, 0., pj.PathLength(nil))
+}
+
+func TestPJ_DistanceMatrix(t *testing.T) {
+	if proj.VersionMajor < 7 {
+		t.Skip("distance functions not tested")
+	}
+
+	defer runtime.GC()
+
+	pj, err := proj.New("epsg:4326")
+	assert.NoError(t, err)
+
+	points := []proj.Coord{
+		bernEPSG4326.DegToRad(),
+		zurichEPSG4326.DegToRad(),
+		newYorkEPSG4326.DegToRad(),
+		parisEPSG4326.DegToRad(),
+	}
+	matrix := pj.DistanceMatrix(points)
+	assert.Equal(t, len(points), len(matrix))
+	for i := range points {
+		assert.Equal(t, len(points), len(matrix[i]))
+		assert.Equal(t, 0., matrix[i][i])
+		for j := range points {
+			if i != j {
+				assertInDelta(t, pj.LPDist(points[i], points[j]), matrix[i][j], 1e-6)
+			}
+		}
+	}
+
+	assert.Zero(t, pj.DistanceMatrix(nil))
+}
+
+func TestPJ_AngularInput(t *testing.T) {
+	defer runtime.GC()
+
+	pj, err := proj.New("+proj=utm +zone=32 +ellps=GRS80")
+	assert.NoError(t, err)
+
+	assert.True(t, pj.AngularInput(proj.DirectionFwd))
+	assert.False(t, pj.AngularOutput(proj.DirectionFwd))
+	assert.False(t, pj.AngularInput(proj.DirectionInv))
+	assert.True(t, pj.AngularOutput(proj.DirectionInv))
+
+	if !proj.Capabilities().DegreeUnits {
+		_, err := pj.DegreeInput(proj.DirectionFwd)
+		assert.IsError(t, err, proj.ErrUnsupported)
+		return
+	}
+
+	degreeInput, err := pj.DegreeInput(proj.DirectionFwd)
+	assert.NoError(t, err)
+	assert.False(t, degreeInput)
+
+	pj, err = proj.NewCRSToCRS("EPSG:4326", "EPSG:2056", nil)
+	assert.NoError(t, err)
+
+	degreeInput, err = pj.DegreeInput(proj.DirectionFwd)
+	assert.NoError(t, err)
+	assert.True(t, degreeInput)
+	degreeOutput, err := pj.DegreeOutput(proj.DirectionFwd)
+	assert.NoError(t, err)
+	assert.False(t, degreeOutput)
+	degreeOutput, err = pj.DegreeOutput(proj.DirectionInv)
+	assert.NoError(t, err)
+	assert.True(t, degreeOutput)
+}
+
+func TestPJ_SetNormalizeUnits(t *testing.T) {
+	defer runtime.GC()
+
+	pj, err := proj.New("+proj=utm +zone=32 +ellps=GRS80")
+	assert.NoError(t, err)
+	assert.False(t, pj.NormalizeUnits())
+
+	pj.SetNormalizeUnits(true)
+	assert.True(t, pj.NormalizeUnits())
+
+	projected, err := pj.Forward(proj.NewCoord(9, 47, 0, 0))
+	assert.NoError(t, err)
+	assertInDelta(t, 500000, projected.X(), 1e-3)
+
+	geographic, err := pj.Inverse(projected)
+	assert.NoError(t, err)
+	assertInDelta(t, 9, geographic.X(), 1e-9)
+	assertInDelta(t, 47, geographic.Y(), 1e-9)
+
+	coords := []proj.Coord{proj.NewCoord(9, 47, 0, 0), proj.NewCoord(10, 48, 0, 0)}
+	assert.NoError(t, pj.ForwardArray(coords))
+	assertInDelta(t, 500000, coords[0].X(), 1e-3)
+	assert.NoError(t, pj.InverseArray(coords))
+	assertInDelta(t, 10, coords[1].X(), 1e-9)
+	assertInDelta(t, 48, coords[1].Y(), 1e-9)
+
+	flatCoords := []float64{9, 47, 10, 48}
+	assert.NoError(t, pj.ForwardFlatCoords(flatCoords, 2, -1, -1))
+	assertInDelta(t, 500000, flatCoords[0], 1e-3)
+	assert.NoError(t, pj.InverseFlatCoords(flatCoords, 2, -1, -1))
+	assertInDelta(t, 10, flatCoords[2], 1e-9)
+	assertInDelta(t, 48, flatCoords[3], 1e-9)
+
+	// Coordinates in degrees are not converted.
+	pj, err = proj.NewCRSToCRS("EPSG:4326", "EPSG:2056", nil)
+	assert.NoError(t, err)
+	pj.SetNormalizeUnits(true)
+
+	actual, err := pj.Forward(bernEPSG4326)
+	assert.NoError(t, err)
+	assertInDelta(t, bernEPSG2056.X(), actual.X(), 1e-3)
+	assertInDelta(t, bernEPSG2056.Y(), actual.Y(), 1e-3)
+}
+
+func TestPJ_RoundTrip(t *testing.T) {
+	defer runtime.GC()
+
+	pj, err := proj.New("+proj=utm +zone=32 +ellps=GRS80")
+	assert.NoError(t, err)
+
+	coord := proj.NewCoord(9, 47, 0, 0)
+	drift, err := pj.RoundTrip(proj.DirectionFwd, 10, coord.DegToRad())
+	assert.NoError(t, err)
+	assertInDelta(t, 0, drift, 1e-6)
+
+	_, err = pj.RoundTrip(proj.DirectionFwd, 0, coord.DegToRad())
+	assert.Error(t, err)
+}
+
+func TestPJ_RoundTripReport(t *testing.T) {
+	defer runtime.GC()
+
+	pj, err := proj.New("+proj=merc +R=6371000")
+	assert.NoError(t, err)
+
+	report, err := pj.RoundTripReport(proj.DirectionFwd, 1, []proj.Coord{
+		proj.NewCoord(0.1, 0.5, 0, 0),
+		proj.NewCoord(0.2, 2, 0, 0),
+		proj.NewCoord(0.3, 1, 0, 0),
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, len(report.Drifts))
+	assertInDelta(t, 0, report.Drifts[0], 1e-6)
+	assert.True(t, math.IsInf(report.Drifts[1], 1))
+	assertInDelta(t, 0, report.Drifts[2], 1e-6)
+	assert.True(t, math.IsInf(report.MaxDrift, 1))
+	assert.Equal(t, 1, report.MaxIndex)
+
+	report, err = pj.RoundTripReport(proj.DirectionInv, 10, []proj.Coord{
+		proj.NewCoord(1e6, 5e6, 0, 0),
+		proj.NewCoord(-2e6, 1e6, 0, 0),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(report.Drifts))
+	assertInDelta(t, 0, report.MaxDrift, 1e-6)
+
+	report, err = pj.RoundTripReport(proj.DirectionFwd, 1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0., report.MaxDrift)
+
+	_, err = pj.RoundTripReport(proj.DirectionFwd, 0, nil)
+	assert.Error(t, err)
+}
+
 func TestPJ_Trans(t *testing.T) {
 	for _, tc := range []struct {
 		name        string
@@ -298,8 +503,8 @@ func TestPJ_TransArray(t *testing.T) {
 }
 
 func TestPJ_TransBounds(t *testing.T) {
-	if proj.VersionMajor < 8 || proj.VersionMajor == 8 && proj.VersionMinor < 2 {
-		t.Skip()
+	if !proj.Capabilities().TransBounds {
+		t.Skip("proj_trans_bounds not supported")
 	}
 
 	defer runtime.GC()
@@ -354,6 +559,74 @@ func TestPJ_TransBounds(t *testing.T) {
 	}
 }
 
+func TestPJ_TransBoundsDensified(t *testing.T) {
+	defer runtime.GC()
+
+	pj, err := proj.NewCRSToCRS("EPSG:4326", "EPSG:2056", nil)
+	assert.NoError(t, err)
+
+	sourceBounds := proj.Bounds{
+		XMin: bernEPSG4326.X(),
+		YMin: bernEPSG4326.Y(),
+		XMax: zurichEPSG4326.X(),
+		YMax: zurichEPSG4326.Y(),
+	}
+	targetBounds, err := pj.TransBoundsDensified(proj.DirectionFwd, sourceBounds, 21)
+	assert.NoError(t, err)
+	assertInDelta(t, bernEPSG2056.X(), targetBounds.XMin, 1e3)
+	assertInDelta(t, bernEPSG2056.Y(), targetBounds.YMin, 1e3)
+	assertInDelta(t, zurichEPSG2056.X(), targetBounds.XMax, 1e3)
+	assertInDelta(t, zurichEPSG2056.Y(), targetBounds.YMax, 1e3)
+
+	inverseBounds, err := pj.TransBoundsDensified(proj.DirectionInv, targetBounds, 21)
+	assert.NoError(t, err)
+
+	// The fallback matches proj_trans_bounds where it is available.
+	if proj.Capabilities().TransBounds {
+		expected, err := pj.ForwardBounds(sourceBounds, 21)
+		assert.NoError(t, err)
+		assertInDelta(t, expected.XMin, targetBounds.XMin, 1e-2)
+		assertInDelta(t, expected.YMin, targetBounds.YMin, 1e-2)
+		assertInDelta(t, expected.XMax, targetBounds.XMax, 1e-2)
+		assertInDelta(t, expected.YMax, targetBounds.YMax, 1e-2)
+
+		expected, err = pj.InverseBounds(targetBounds, 21)
+		assert.NoError(t, err)
+		assertInDelta(t, expected.XMin, inverseBounds.XMin, 1e-7)
+		assertInDelta(t, expected.YMin, inverseBounds.YMin, 1e-7)
+		assertInDelta(t, expected.XMax, inverseBounds.XMax, 1e-7)
+		assertInDelta(t, expected.YMax, inverseBounds.YMax, 1e-7)
+	}
+
+	// Bounds that cannot be transformed at all, here with latitudes beyond the
+	// poles, are an error.
+	_, err = pj.TransBoundsDensified(proj.DirectionFwd, proj.Bounds{XMin: 100, YMin: 8, XMax: 120, YMax: 9}, 0)
+	assert.Error(t, err)
+}
+
+func TestPJ_GetLastUsedOperation(t *testing.T) {
+	defer runtime.GC()
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	pj, err := context.NewCRSToCRS("EPSG:4326", "EPSG:2056", nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, pj)
+
+	_, err = pj.Forward(bernEPSG4326)
+	assert.NoError(t, err)
+
+	operation, err := pj.GetLastUsedOperation()
+	if !proj.Capabilities().LastUsedOperation {
+		assert.IsError(t, err, proj.ErrUnsupported)
+		assert.Zero(t, operation)
+		return
+	}
+	assert.NoError(t, err)
+	assert.NotZero(t, operation)
+}
+
 func TestPJ_TransFlatCoords(t *testing.T) {
 	defer runtime.GC()
 
@@ -2,12 +2,21 @@
 package proj
 
 // #cgo pkg-config: proj
+// #include <stdlib.h>
 // #include "go-proj.h"
 import "C"
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"math"
+	"os"
+	"path/filepath"
 	"runtime"
+	"unsafe"
+
+	"github.com/michiho/go-proj/v10/grids"
 )
 
 // Version.
@@ -17,9 +26,73 @@ const (
 	VersionPatch = C.PROJ_VERSION_PATCH
 )
 
+// ErrUnsupported is returned when a feature is not supported by the version of
+// PROJ that the package was compiled against.
+var ErrUnsupported = fmt.Errorf("PROJ %d.%d.%d: %w", VersionMajor, VersionMinor, VersionPatch, errors.ErrUnsupported)
+
+// A CapabilitySet reports which optional features are supported by the version
+// of PROJ that the package was compiled against.
+type CapabilitySet struct {
+	TransBounds           bool // proj_trans_bounds, PROJ 8.2 and later. Otherwise TransBounds falls back to a Go implementation.
+	LastUsedOperation     bool // proj_trans_get_last_used_operation, PROJ 9.1 and later.
+	UserWritableDirectory bool // proj_context_get_user_writable_directory, PROJ 7.0 and later.
+	InsertStatements      bool // proj_get_insert_statements and proj_context_get_database_structure, PROJ 8.1 and later.
+	Network               bool // Network access to remote resources, PROJ 7.0 and later.
+	CABundlePath          bool // proj_context_set_ca_bundle_path, PROJ 7.2 and later.
+	GridCache             bool // Configuration of the cache of remote grids, PROJ 7.0 and later.
+	FileAPI               bool // proj_context_set_fileapi, PROJ 7.0 and later.
+	DegreeUnits           bool // proj_degree_input and proj_degree_output, PROJ 7.1 and later.
+	CoordinateMetadata    bool // proj_coordinate_metadata_create and proj_coordinate_metadata_get_epoch, PROJ 9.2 and later.
+	PointMotion           bool // Point motion operations between coordinate epochs, PROJ 9.4 and later.
+	GeoidModels           bool // proj_get_geoid_models_from_database, PROJ 8.1 and later.
+	Topocentric           bool // The topocentric conversion, PROJ 8.0 and later.
+}
+
+// An Info contains information about the PROJ library linked at runtime.
+type Info struct {
+	Major      int      // Major release number.
+	Minor      int      // Minor release number.
+	Patch      int      // Patch release number.
+	Release    string   // Release info, e.g. "Rel. 9.1.1, December 1st, 2022".
+	Version    string   // Version number, e.g. "9.1.1".
+	SearchPath string   // Search path for PROJ, joined with the OS path separator.
+	Paths      []string // Paths where PROJ looks for resource files.
+}
+
+// A GridFileInfo describes a grid file found by PROJ. Bounds and cell sizes
+// are in degrees for geographic grids, and in the units of the grid's CRS
+// otherwise.
+type GridFileInfo struct {
+	GridName      string  // Name of the grid as requested.
+	Filename      string  // Full path of the grid file.
+	Format        string  // Format of the grid file, e.g. "gtiff", "ntv2" or "gtx".
+	LowerLeftLon  float64 // Longitude of the lower left corner.
+	LowerLeftLat  float64 // Latitude of the lower left corner.
+	UpperRightLon float64 // Longitude of the upper right corner.
+	UpperRightLat float64 // Latitude of the upper right corner.
+	NumLon        int     // Number of grid cells in longitude direction.
+	NumLat        int     // Number of grid cells in latitude direction.
+	CellSizeLon   float64 // Cell size in longitude direction.
+	CellSizeLat   float64 // Cell size in latitude direction.
+	Geographic    bool    // Whether the grid's CRS is geographic, always true for grids read from the network.
+}
+
+// An InitFileInfo describes an init file found by PROJ.
+type InitFileInfo struct {
+	Name       string // Name of the init file as requested.
+	Filename   string // Full path of the init file, if any.
+	Version    string // Version of the init file.
+	Origin     string // Originating entity of the init file, e.g. "EPSG".
+	LastUpdate string // Date of the last update of the init file.
+}
+
 // An Area is an area.
 type Area struct {
-	pjArea *C.PJ_AREA
+	pjArea         *C.PJ_AREA
+	westLonDegree  float64
+	southLatDegree float64
+	eastLonDegree  float64
+	northLatDegree float64
 }
 
 type Bounds struct {
@@ -43,7 +116,11 @@ func NewArea(westLonDegree, southLatDegree, eastLonDegree, northLatDegree float6
 	pjArea := C.proj_area_create()
 	C.proj_area_set_bbox(pjArea, (C.double)(westLonDegree), (C.double)(southLatDegree), (C.double)(eastLonDegree), (C.double)(northLatDegree))
 	a := &Area{
-		pjArea: pjArea,
+		pjArea:         pjArea,
+		westLonDegree:  westLonDegree,
+		southLatDegree: southLatDegree,
+		eastLonDegree:  eastLonDegree,
+		northLatDegree: northLatDegree,
 	}
 	runtime.SetFinalizer(a, (*Area).Destroy)
 	return a
@@ -57,6 +134,114 @@ func (a *Area) Destroy() {
 	}
 }
 
+// Capabilities returns the optional features supported by the version of PROJ
+// that the package was compiled against. Methods that depend on an unsupported
+// feature return ErrUnsupported.
+func Capabilities() CapabilitySet {
+	return CapabilitySet{
+		TransBounds:           versionAtLeast(8, 2),
+		LastUsedOperation:     versionAtLeast(9, 1),
+		UserWritableDirectory: versionAtLeast(7, 0),
+		InsertStatements:      versionAtLeast(8, 1),
+		Network:               versionAtLeast(7, 0),
+		CABundlePath:          versionAtLeast(7, 2),
+		GridCache:             versionAtLeast(7, 0),
+		FileAPI:               versionAtLeast(7, 0),
+		DegreeUnits:           versionAtLeast(7, 1),
+		CoordinateMetadata:    versionAtLeast(9, 2),
+		PointMotion:           versionAtLeast(9, 4),
+		GeoidModels:           versionAtLeast(8, 1),
+		Topocentric:           versionAtLeast(8, 0),
+	}
+}
+
+// RuntimeInfo returns information about the PROJ library linked at runtime,
+// which may differ from the version the package was compiled against.
+func RuntimeInfo() Info {
+	defaultContext.Lock()
+	defer defaultContext.Unlock()
+
+	cInfo := C.proj_info()
+	paths := make([]string, 0, int(cInfo.path_count))
+	if cInfo.path_count > 0 {
+		cPaths := unsafe.Slice(cInfo.paths, cInfo.path_count)
+		for _, cPath := range cPaths {
+			paths = append(paths, C.GoString(cPath))
+		}
+	}
+	return Info{
+		Major:      int(cInfo.major),
+		Minor:      int(cInfo.minor),
+		Patch:      int(cInfo.patch),
+		Release:    C.GoString(cInfo.release),
+		Version:    C.GoString(cInfo.version),
+		SearchPath: C.GoString(cInfo.searchpath),
+		Paths:      paths,
+	}
+}
+
+// GridInfo returns information about the grid file name. It does not follow
+// the rules by which PROJ finds grids for c: c's network access and the file
+// system set with SetFileSystem are ignored, and a relative name is only
+// looked up as a local file in c's user writable directory and in the search
+// paths set with SetSearchPaths. If no search paths were set then name is
+// looked up by PROJ with its default context, which may read the grid from
+// the network. It returns an error wrapping fs.ErrNotExist if the grid is not
+// found.
+func (c *Context) GridInfo(name string) (*GridFileInfo, error) {
+	c.Lock()
+	searchPaths := c.searchPaths
+	c.Unlock()
+
+	filename := name
+	if c != defaultContext && searchPaths != nil && !filepath.IsAbs(name) {
+		var err error
+		if filename, err = c.findFile(name, searchPaths); err != nil {
+			return nil, err
+		}
+	}
+
+	gridInfo, err := projGridInfo(filename)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	gridInfo.GridName = name
+	return gridInfo, nil
+}
+
+// GridInfo returns information about the grid file name using the default
+// context.
+func GridInfo(name string) (*GridFileInfo, error) {
+	return defaultContext.GridInfo(name)
+}
+
+// InitInfo returns information about the init file name, for example "epsg"
+// or "ITRF2014". name is looked up in PROJ's default search paths, not in those
+// of any Context. It returns an error wrapping fs.ErrNotExist if the init file
+// is not found.
+func InitInfo(name string) (*InitFileInfo, error) {
+	defaultContext.Lock()
+	defer defaultContext.Unlock()
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	cInitInfo := C.proj_init_info(cName)
+	initInfo := &InitFileInfo{
+		Name:       C.GoString(&cInitInfo.name[0]),
+		Filename:   C.GoString(&cInitInfo.filename[0]),
+		Version:    C.GoString(&cInitInfo.version[0]),
+		Origin:     C.GoString(&cInitInfo.origin[0]),
+		LastUpdate: C.GoString(&cInitInfo.lastupdate[0]),
+	}
+	// The "epsg" and "IGNF" init files are served from the database, so they
+	// may have an origin but no filename.
+	if initInfo.Filename == "" && initInfo.Origin == "" {
+		return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	return initInfo, nil
+}
+
 // NewCoord returns a new Coord.
 func NewCoord(x, y, z, m float64) Coord {
 	return Coord{x, y, z, m}
@@ -74,6 +259,26 @@ func (c *Coord) RadToDeg() Coord {
 	return Coord{180 * c[0] / math.Pi, 180 * c[1] / math.Pi, c[2], c[3]}
 }
 
+// coordsDegToRad returns new Coords with the first two elements of coords
+// transformed from degrees to radians.
+func coordsDegToRad(coords []Coord) []Coord {
+	result := make([]Coord, len(coords))
+	for i := range coords {
+		result[i] = coords[i].DegToRad()
+	}
+	return result
+}
+
+// coordsRadToDeg returns new Coords with the first two elements of coords
+// transformed from radians to degrees.
+func coordsRadToDeg(coords []Coord) []Coord {
+	result := make([]Coord, len(coords))
+	for i := range coords {
+		result[i] = coords[i].RadToDeg()
+	}
+	return result
+}
+
 // X returns c's X coordinate.
 func (c *Coord) X() float64 { return c[0] }
 
@@ -86,6 +291,77 @@ func (c *Coord) Z() float64 { return c[2] }
 // M returns c's M coordinate.
 func (c *Coord) M() float64 { return c[3] }
 
+// projGridInfo returns information about the grid file name with
+// proj_grid_info, which always uses PROJ's default context. Bounds and cell
+// sizes are converted from radians to degrees for geographic grids.
+func projGridInfo(name string) (*GridFileInfo, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	defaultContext.Lock()
+	cGridInfo := C.proj_grid_info(cName)
+	defaultContext.Unlock()
+
+	gridInfo := &GridFileInfo{
+		Filename:      C.GoString(&cGridInfo.filename[0]),
+		Format:        C.GoString(&cGridInfo.format[0]),
+		LowerLeftLon:  float64(cGridInfo.lowerleft.lam),
+		LowerLeftLat:  float64(cGridInfo.lowerleft.phi),
+		UpperRightLon: float64(cGridInfo.upperright.lam),
+		UpperRightLat: float64(cGridInfo.upperright.phi),
+		NumLon:        int(cGridInfo.n_lon),
+		NumLat:        int(cGridInfo.n_lat),
+		CellSizeLon:   float64(cGridInfo.cs_lon),
+		CellSizeLat:   float64(cGridInfo.cs_lat),
+		Geographic:    true,
+	}
+	if gridInfo.Filename == "" {
+		return nil, fs.ErrNotExist
+	}
+
+	// Only GeoTIFF grids may be projected, in which case PROJ reports their
+	// bounds and cell sizes in the units of their CRS. Grids that PROJ read
+	// from the network cannot be checked and are assumed to be geographic.
+	if gridInfo.Format == "gtiff" && isLocalPath(gridInfo.Filename) {
+		header, err := grids.ReadHeaderFile(gridInfo.Filename)
+		if err != nil {
+			return nil, err
+		}
+		gridInfo.Geographic = len(header.SubGrids) == 0 || !header.SubGrids[0].Projected
+	}
+	if gridInfo.Geographic {
+		gridInfo.LowerLeftLon *= 180 / math.Pi
+		gridInfo.LowerLeftLat *= 180 / math.Pi
+		gridInfo.UpperRightLon *= 180 / math.Pi
+		gridInfo.UpperRightLat *= 180 / math.Pi
+		gridInfo.CellSizeLon *= 180 / math.Pi
+		gridInfo.CellSizeLat *= 180 / math.Pi
+	}
+	return gridInfo, nil
+}
+
+// findFile returns the path of the resource file name in c's user writable
+// directory or in searchPaths.
+func (c *Context) findFile(name string, searchPaths []string) (string, error) {
+	dirs := searchPaths
+	if userWritableDirectory, err := c.UserWritableDirectory(false); err == nil {
+		dirs = append([]string{userWritableDirectory}, dirs...)
+	}
+	for _, dir := range dirs {
+		filename := filepath.Join(dir, name)
+		if _, err := os.Stat(filename); err == nil {
+			return filename, nil
+		}
+	}
+	return "", fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+}
+
+// versionAtLeast returns whether the package was compiled against PROJ major.minor
+// or later.
+func versionAtLeast(major, minor int) bool {
+	return VersionMajor > major || VersionMajor == major && VersionMinor >= minor
+}
+
 func (e *Error) Error() string {
 	return e.context.errnoString(e.errno)
 }
@@ -1,7 +1,13 @@
 package proj_test
 
 import (
+	"encoding/binary"
+	"errors"
+	"io/fs"
 	"math"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
@@ -17,3 +23,111 @@ func TestRadToDeg(t *testing.T) {
 	assert.Equal(t, 1., actual.Z())
 	assert.Equal(t, 2., actual.M())
 }
+
+func TestRuntimeInfo(t *testing.T) {
+	info := proj.RuntimeInfo()
+	assert.Equal(t, proj.VersionMajor, info.Major)
+	assert.NotEqual(t, "", info.Version)
+	assert.NotEqual(t, "", info.Release)
+}
+
+func TestCapabilities(t *testing.T) {
+	defer runtime.GC()
+
+	capabilities := proj.Capabilities()
+	assert.True(t, errors.Is(proj.ErrUnsupported, errors.ErrUnsupported))
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+
+	_, err := context.UserWritableDirectory(false)
+	assert.Equal(t, !capabilities.UserWritableDirectory, errors.Is(err, proj.ErrUnsupported))
+
+	pj, err := context.NewCRSToCRS("EPSG:4326", "EPSG:2056", nil)
+	assert.NoError(t, err)
+
+	_, err = pj.GetLastUsedOperation()
+	assert.Equal(t, !capabilities.LastUsedOperation, errors.Is(err, proj.ErrUnsupported))
+
+	// TransBounds falls back to a Go implementation rather than returning
+	// zero bounds.
+	bounds, err := pj.ForwardBounds(proj.Bounds{
+		XMin: bernEPSG4326.X(),
+		YMin: bernEPSG4326.Y(),
+		XMax: zurichEPSG4326.X(),
+		YMax: zurichEPSG4326.Y(),
+	}, 21)
+	assert.NoError(t, err)
+	assert.NotZero(t, bounds)
+}
+
+func TestGridInfo(t *testing.T) {
+	filename := writeTestGTXGrid(t, t.TempDir())
+
+	gridInfo, err := proj.GridInfo(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, filename, gridInfo.Filename)
+	assert.Equal(t, "gtx", gridInfo.Format)
+	assert.Equal(t, 4, gridInfo.NumLon)
+	assert.Equal(t, 3, gridInfo.NumLat)
+	assertInDelta(t, 8, gridInfo.LowerLeftLon, 1e-9)
+	assertInDelta(t, 47, gridInfo.LowerLeftLat, 1e-9)
+	assertInDelta(t, 0.5, gridInfo.CellSizeLon, 1e-9)
+	assertInDelta(t, 0.5, gridInfo.CellSizeLat, 1e-9)
+
+	_, err = proj.GridInfo("go_proj_missing_grid.tif")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestContext_GridInfo(t *testing.T) {
+	defer runtime.GC()
+
+	dir := t.TempDir()
+	filename := writeTestGTXGrid(t, dir)
+	name := filepath.Base(filename)
+
+	context := proj.NewContext()
+	assert.NotZero(t, context)
+	context.SetSearchPaths([]string{dir})
+
+	gridInfo, err := context.GridInfo(name)
+	assert.NoError(t, err)
+	assert.Equal(t, name, gridInfo.GridName)
+	assert.Equal(t, filename, gridInfo.Filename)
+	assert.True(t, gridInfo.Geographic)
+	assertInDelta(t, 8, gridInfo.LowerLeftLon, 1e-9)
+	assertInDelta(t, 0.5, gridInfo.CellSizeLat, 1e-9)
+
+	// Other contexts do not look in the search paths of context.
+	_, err = proj.GridInfo(name)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+// writeTestGTXGrid writes a GTX grid of 3 rows and 4 columns with its lower
+// left corner at 47N 8E and a cell size of 0.5 degrees into dir and returns
+// its filename.
+func writeTestGTXGrid(t *testing.T, dir string) string {
+	t.Helper()
+	var data []byte
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(47))
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(8))
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(0.5))
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(0.5))
+	data = binary.BigEndian.AppendUint32(data, 3)
+	data = binary.BigEndian.AppendUint32(data, 4)
+	for i := 0; i < 3*4; i++ {
+		data = binary.BigEndian.AppendUint32(data, math.Float32bits(float32(i)))
+	}
+	filename := filepath.Join(dir, "go_proj_test_grid.gtx")
+	assert.NoError(t, os.WriteFile(filename, data, 0o644))
+	return filename
+}
+
+func TestInitInfo(t *testing.T) {
+	initInfo, err := proj.InitInfo("epsg")
+	assert.NoError(t, err)
+	assert.Equal(t, "EPSG", initInfo.Origin)
+
+	_, err = proj.InitInfo("go_proj_missing_init")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
@@ -0,0 +1,120 @@
+package proj
+
+import (
+	"strconv"
+)
+
+// NewGeocentric returns a new PJ that converts geodetic coordinates on
+// ellipsoid, e.g. "WGS84" or "GRS80", to Earth-centered, Earth-fixed (ECEF)
+// coordinates in the forward direction. Geodetic coordinates are longitude and
+// latitude in degrees and ellipsoidal height in meters. ECEF coordinates are
+// X, Y, and Z in meters.
+func (c *Context) NewGeocentric(ellipsoid string) (*PJ, error) {
+	return c.NewFromArgs(
+		"proj=pipeline",
+		"step", "proj=unitconvert", "xy_in=deg", "xy_out=rad",
+		"step", "proj=cart", "ellps="+ellipsoid,
+	)
+}
+
+// NewTopocentric returns a new PJ that converts geodetic coordinates on
+// ellipsoid to local East-North-Up (ENU) coordinates around origin in the
+// forward direction. origin and the geodetic coordinates are longitude and
+// latitude in degrees and ellipsoidal height in meters, in that order
+// regardless of the axis order of any CRS. ENU coordinates are east, north,
+// and up in meters.
+func (c *Context) NewTopocentric(origin Coord, ellipsoid string) (*PJ, error) {
+	if !Capabilities().Topocentric {
+		return nil, ErrUnsupported
+	}
+	return c.NewFromArgs(
+		"proj=pipeline",
+		"step", "proj=unitconvert", "xy_in=deg", "xy_out=rad",
+		"step", "proj=cart", "ellps="+ellipsoid,
+		"step", "proj=topocentric", "ellps="+ellipsoid,
+		"lon_0="+formatArg(origin[0]), "lat_0="+formatArg(origin[1]), "h_0="+formatArg(origin[2]),
+	)
+}
+
+// ToECEF converts coords from geodetic coordinates on ellipsoid to ECEF
+// coordinates in place. See NewGeocentric.
+func (c *Context) ToECEF(coords []Coord, ellipsoid string) error {
+	pj, err := c.NewGeocentric(ellipsoid)
+	if err != nil {
+		return err
+	}
+	defer pj.Destroy()
+	return pj.TransArray(DirectionFwd, coords)
+}
+
+// FromECEF converts coords from ECEF coordinates to geodetic coordinates on
+// ellipsoid in place. See NewGeocentric.
+func (c *Context) FromECEF(coords []Coord, ellipsoid string) error {
+	pj, err := c.NewGeocentric(ellipsoid)
+	if err != nil {
+		return err
+	}
+	defer pj.Destroy()
+	return pj.TransArray(DirectionInv, coords)
+}
+
+// ToENU converts coords from geodetic coordinates on ellipsoid to ENU
+// coordinates around origin in place. See NewTopocentric.
+func (c *Context) ToENU(coords []Coord, origin Coord, ellipsoid string) error {
+	pj, err := c.NewTopocentric(origin, ellipsoid)
+	if err != nil {
+		return err
+	}
+	defer pj.Destroy()
+	return pj.TransArray(DirectionFwd, coords)
+}
+
+// FromENU converts coords from ENU coordinates around origin to geodetic
+// coordinates on ellipsoid in place. See NewTopocentric.
+func (c *Context) FromENU(coords []Coord, origin Coord, ellipsoid string) error {
+	pj, err := c.NewTopocentric(origin, ellipsoid)
+	if err != nil {
+		return err
+	}
+	defer pj.Destroy()
+	return pj.TransArray(DirectionInv, coords)
+}
+
+// NewGeocentric returns a new geodetic to ECEF PJ using the default context.
+func NewGeocentric(ellipsoid string) (*PJ, error) {
+	return defaultContext.NewGeocentric(ellipsoid)
+}
+
+// NewTopocentric returns a new geodetic to ENU PJ using the default context.
+func NewTopocentric(origin Coord, ellipsoid string) (*PJ, error) {
+	return defaultContext.NewTopocentric(origin, ellipsoid)
+}
+
+// ToECEF converts coords from geodetic to ECEF coordinates using the default
+// context.
+func ToECEF(coords []Coord, ellipsoid string) error {
+	return defaultContext.ToECEF(coords, ellipsoid)
+}
+
+// FromECEF converts coords from ECEF to geodetic coordinates using the default
+// context.
+func FromECEF(coords []Coord, ellipsoid string) error {
+	return defaultContext.FromECEF(coords, ellipsoid)
+}
+
+// ToENU converts coords from geodetic to ENU coordinates using the default
+// context.
+func ToENU(coords []Coord, origin Coord, ellipsoid string) error {
+	return defaultContext.ToENU(coords, origin, ellipsoid)
+}
+
+// FromENU converts coords from ENU to geodetic coordinates using the default
+// context.
+func FromENU(coords []Coord, origin Coord, ellipsoid string) error {
+	return defaultContext.FromENU(coords, origin, ellipsoid)
+}
+
+// formatArg formats value as a PROJ argument.
+func formatArg(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
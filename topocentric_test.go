@@ -0,0 +1,124 @@
+package proj_test
+
+import (
+	"math"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+func TestToECEF(t *testing.T) {
+	defer runtime.GC()
+
+	coords := []proj.Coord{
+		proj.NewCoord(0, 0, 0, 0),
+		proj.NewCoord(90, 0, 0, 0),
+		proj.NewCoord(0, 90, 0, 0),
+		proj.NewCoord(8.5, 47.4, 500, 0),
+	}
+	assert.NoError(t, proj.ToECEF(coords, "WGS84"))
+	for i, expected := range []proj.Coord{
+		proj.NewCoord(6378137, 0, 0, 0),
+		proj.NewCoord(0, 6378137, 0, 0),
+		proj.NewCoord(0, 0, 6356752.314245179, 0),
+		proj.NewCoord(4277886.565892902, 639334.4309306759, 4672347.459225975, 0),
+	} {
+		assertInDelta(t, expected.X(), coords[i].X(), 1e-6)
+		assertInDelta(t, expected.Y(), coords[i].Y(), 1e-6)
+		assertInDelta(t, expected.Z(), coords[i].Z(), 1e-6)
+	}
+
+	assert.NoError(t, proj.FromECEF(coords, "WGS84"))
+	assertInDelta(t, 8.5, coords[3].X(), 1e-9)
+	assertInDelta(t, 47.4, coords[3].Y(), 1e-9)
+	assertInDelta(t, 500, coords[3].Z(), 1e-6)
+
+	assert.Error(t, proj.ToECEF(coords, "not an ellipsoid"))
+}
+
+func TestToENU(t *testing.T) {
+	if !proj.Capabilities().Topocentric {
+		t.Skip("topocentric conversion not supported")
+	}
+
+	defer runtime.GC()
+
+	for i, tc := range []struct {
+		origin    proj.Coord
+		ellipsoid string
+		a, f      float64
+	}{
+		{origin: proj.NewCoord(0, 0, 0, 0), ellipsoid: "WGS84", a: 6378137, f: 1 / 298.257223563},
+		{origin: proj.NewCoord(8.5, 47.4, 500, 0), ellipsoid: "GRS80", a: 6378137, f: 1 / 298.257222101},
+		{origin: proj.NewCoord(-120, -33, -20, 0), ellipsoid: "GRS80", a: 6378137, f: 1 / 298.257222101},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			coords := []proj.Coord{
+				tc.origin,
+				proj.NewCoord(tc.origin.X(), tc.origin.Y(), tc.origin.Z()+100, 0),
+				proj.NewCoord(tc.origin.X()+0.001, tc.origin.Y(), tc.origin.Z(), 0),
+				proj.NewCoord(tc.origin.X()+0.01, tc.origin.Y()+0.01, tc.origin.Z()+10, 0),
+			}
+			expected := make([]proj.Coord, len(coords))
+			for i, coord := range coords {
+				expected[i] = ecefToENU(geodeticToECEF(coord, tc.a, tc.f), tc.origin, tc.a, tc.f)
+			}
+
+			geodetic := append([]proj.Coord(nil), coords...)
+			assert.NoError(t, proj.ToENU(coords, tc.origin, tc.ellipsoid))
+			for i := range coords {
+				assertInDelta(t, expected[i].X(), coords[i].X(), 1e-6)
+				assertInDelta(t, expected[i].Y(), coords[i].Y(), 1e-6)
+				assertInDelta(t, expected[i].Z(), coords[i].Z(), 1e-6)
+			}
+
+			assert.NoError(t, proj.FromENU(coords, tc.origin, tc.ellipsoid))
+			for i := range coords {
+				assertInDelta(t, geodetic[i].X(), coords[i].X(), 1e-9)
+				assertInDelta(t, geodetic[i].Y(), coords[i].Y(), 1e-9)
+				assertInDelta(t, geodetic[i].Z(), coords[i].Z(), 1e-6)
+			}
+		})
+	}
+
+	// The origin is longitude first, so a latitude first origin is a
+	// different point.
+	pj, err := proj.NewTopocentric(proj.NewCoord(47.4, 8.5, 500, 0), "GRS80")
+	assert.NoError(t, err)
+	enu, err := pj.Forward(proj.NewCoord(8.5, 47.4, 500, 0))
+	assert.NoError(t, err)
+	assert.True(t, math.Hypot(enu.X(), enu.Y()) > 1e6)
+}
+
+// geodeticToECEF returns the ECEF coordinates of coord, which is longitude and
+// latitude in degrees and height in meters, on the ellipsoid with semi-major
+// axis a and flattening f.
+func geodeticToECEF(coord proj.Coord, a, f float64) proj.Coord {
+	lon, lat, h := coord.X()*math.Pi/180, coord.Y()*math.Pi/180, coord.Z()
+	e2 := f * (2 - f)
+	n := a / math.Sqrt(1-e2*math.Sin(lat)*math.Sin(lat))
+	return proj.NewCoord(
+		(n+h)*math.Cos(lat)*math.Cos(lon),
+		(n+h)*math.Cos(lat)*math.Sin(lon),
+		(n*(1-e2)+h)*math.Sin(lat),
+		0,
+	)
+}
+
+// ecefToENU returns the ENU coordinates of the ECEF coordinates ecef around
+// origin, which is longitude and latitude in degrees and height in meters.
+func ecefToENU(ecef, origin proj.Coord, a, f float64) proj.Coord {
+	originECEF := geodeticToECEF(origin, a, f)
+	dx, dy, dz := ecef.X()-originECEF.X(), ecef.Y()-originECEF.Y(), ecef.Z()-originECEF.Z()
+	lon, lat := origin.X()*math.Pi/180, origin.Y()*math.Pi/180
+	return proj.NewCoord(
+		-math.Sin(lon)*dx+math.Cos(lon)*dy,
+		-math.Sin(lat)*math.Cos(lon)*dx-math.Sin(lat)*math.Sin(lon)*dy+math.Cos(lat)*dz,
+		math.Cos(lat)*math.Cos(lon)*dx+math.Cos(lat)*math.Sin(lon)*dy+math.Sin(lat)*dz,
+		0,
+	)
+}
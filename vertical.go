@@ -0,0 +1,214 @@
+package proj
+
+// #include <stdlib.h>
+// #include "go-proj.h"
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// A VerticalTransformer converts ellipsoidal heights to orthometric heights
+// with a geoid model, e.g. from EPSG:4979 to EPSG:4326+5773. Coordinates are
+// longitude and latitude in degrees and heights in meters.
+type VerticalTransformer struct {
+	pj         *PJ
+	GeoidModel string     // Geoid model of the vertical CRS in the database whose transformations are used, e.g. "GEOID18", empty if none.
+	Grids      []GridUsed // Grids of the candidate transformations.
+}
+
+// NewVerticalTransformer returns a new VerticalTransformer from the 3D
+// ellipsoidal CRS ellipsoidalCRS to the compound CRS of horizontalCRS and
+// verticalCRS, e.g. "EPSG:4937", "EPSG:4258", and "EPSG:5783", within the
+// optional area.
+//
+// If the database lists geoid models for verticalCRS, see
+// GeoidModelsFromDatabase, then the transformations of the first model whose
+// grids are available are used. Otherwise, or if the grids of no model are
+// available, all transformations to verticalCRS are candidates. As with
+// NewCRSToCRS, the candidate is chosen for each coordinate, so vertical CRSs
+// with several regional geoid grids are supported.
+//
+// The transformation always applies a geoid grid. If the grids of all
+// candidate transformations are missing then the returned error names them
+// and wraps fs.ErrNotExist, rather than falling back to a transformation that
+// leaves heights unchanged. Coordinates outside the available grids fail to
+// transform.
+func (c *Context) NewVerticalTransformer(ellipsoidalCRS, horizontalCRS, verticalCRS string, area *Area) (*VerticalTransformer, error) {
+	sourcePJ, err := c.New(ellipsoidalCRS)
+	if err != nil {
+		return nil, err
+	}
+	horizontalPJ, err := c.New(horizontalCRS)
+	if err != nil {
+		return nil, err
+	}
+	verticalPJ, err := c.New(verticalCRS)
+	if err != nil {
+		return nil, err
+	}
+
+	var geoidModels []string
+	if srid := verticalPJ.GetSRID(); srid.Auth != "" && Capabilities().GeoidModels {
+		if geoidModels, err = c.GeoidModelsFromDatabase(srid.Auth, srid.Code); err != nil {
+			return nil, err
+		}
+	}
+
+	var targetPJ *PJ
+	var geoidModel string
+	var grids []GridUsed
+	for _, model := range geoidModels {
+		modelPJ, err := c.newVerticalCRSWithGeoidModel(verticalPJ, model)
+		if err != nil {
+			return nil, err
+		}
+		modelTargetPJ, err := c.CreateCompoundCrs("", horizontalPJ, modelPJ)
+		if err != nil {
+			return nil, err
+		}
+		if modelGrids, err := c.availableGrids(sourcePJ, modelTargetPJ, area); err == nil && len(modelGrids) > 0 {
+			targetPJ, geoidModel, grids = modelTargetPJ, model, modelGrids
+			break
+		}
+	}
+
+	if targetPJ == nil {
+		if targetPJ, err = c.CreateCompoundCrs("", horizontalPJ, verticalPJ); err != nil {
+			return nil, err
+		}
+		if grids, err = c.availableGrids(sourcePJ, targetPJ, area); err != nil {
+			return nil, fmt.Errorf("%s to %s+%s: %w", ellipsoidalCRS, horizontalCRS, verticalCRS, err)
+		}
+		if len(grids) == 0 {
+			return nil, fmt.Errorf("%s to %s+%s: no operation using grids found", ellipsoidalCRS, horizontalCRS, verticalCRS)
+		}
+	}
+
+	// Without ballpark operations, coordinates outside the available grids
+	// fail instead of keeping their heights.
+	operation, err := c.NewCRSToCRSFromPJ(sourcePJ, targetPJ, area, "ALLOW_BALLPARK=NO")
+	if err != nil {
+		return nil, err
+	}
+	defer operation.Destroy()
+	pj, err := operation.NormalizeForVisualization()
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerticalTransformer{
+		pj:         pj,
+		GeoidModel: geoidModel,
+		Grids:      grids,
+	}, nil
+}
+
+// NewVerticalTransformer returns a new VerticalTransformer using the default
+// context.
+func NewVerticalTransformer(ellipsoidalCRS, horizontalCRS, verticalCRS string, area *Area) (*VerticalTransformer, error) {
+	return defaultContext.NewVerticalTransformer(ellipsoidalCRS, horizontalCRS, verticalCRS, area)
+}
+
+// GeoidModelsFromDatabase returns the names of the geoid models of the
+// vertical CRS with authName and code in the database, e.g. "GEOID18". The
+// database maps each model to the transformation that implements it.
+func (c *Context) GeoidModelsFromDatabase(authName, code string) ([]string, error) {
+	if !Capabilities().GeoidModels {
+		return nil, ErrUnsupported
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	cAuthName := C.CString(authName)
+	defer C.free(unsafe.Pointer(cAuthName))
+
+	cCode := C.CString(code)
+	defer C.free(unsafe.Pointer(cCode))
+
+	cGeoidModels := C.proj_get_geoid_models_from_database(c.pjContext, cAuthName, cCode, nil)
+	if err := c.checkError(); err != nil {
+		return nil, err
+	}
+	defer C.proj_string_list_destroy(cGeoidModels)
+
+	return nullTerminatedListToGoSlice(cGeoidModels), nil
+}
+
+// newVerticalCRSWithGeoidModel returns a copy of the vertical CRS verticalPJ
+// with the geoid model named model, so that PROJ only considers the
+// transformations of model in the database when transforming to it.
+func (c *Context) newVerticalCRSWithGeoidModel(verticalPJ *PJ, model string) (*PJ, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if verticalPJ.context != c {
+		verticalPJ.context.Lock()
+		defer verticalPJ.context.Unlock()
+	}
+
+	datum := C.proj_crs_get_datum_forced(c.pjContext, verticalPJ.pj)
+	if datum == nil {
+		return nil, c.newError(int(C.proj_context_errno(c.pjContext)))
+	}
+	defer C.proj_destroy(datum)
+
+	cs := C.proj_crs_get_coordinate_system(c.pjContext, verticalPJ.pj)
+	if cs == nil {
+		return nil, c.newError(int(C.proj_context_errno(c.pjContext)))
+	}
+	defer C.proj_destroy(cs)
+
+	var cUnitName *C.char
+	var unitConvFactor C.double
+	if C.proj_cs_get_axis_info(c.pjContext, cs, 0, nil, nil, nil, &unitConvFactor, &cUnitName, nil, nil) == 0 {
+		return nil, c.newError(int(C.proj_context_errno(c.pjContext)))
+	}
+
+	cModel := C.CString(model)
+	defer C.free(unsafe.Pointer(cModel))
+
+	return c.newPJ(C.proj_create_vertical_crs_ex(c.pjContext, C.proj_get_name(verticalPJ.pj),
+		C.proj_get_name(datum), C.proj_get_id_auth_name(datum, 0), C.proj_get_id_code(datum, 0),
+		cUnitName, unitConvFactor, cModel, nil, nil, nil, nil))
+}
+
+// GeoidModelsFromDatabase returns the geoid models of a vertical CRS using the
+// default context.
+func GeoidModelsFromDatabase(authName, code string) ([]string, error) {
+	return defaultContext.GeoidModelsFromDatabase(authName, code)
+}
+
+// PJ returns the transformation of t.
+func (t *VerticalTransformer) PJ() *PJ {
+	return t.pj
+}
+
+// ToOrthometric converts the ellipsoidal heights of coords to orthometric
+// heights in place.
+func (t *VerticalTransformer) ToOrthometric(coords []Coord) error {
+	return t.pj.ForwardArray(coords)
+}
+
+// ToEllipsoidal converts the orthometric heights of coords to ellipsoidal
+// heights in place.
+func (t *VerticalTransformer) ToEllipsoidal(coords []Coord) error {
+	return t.pj.InverseArray(coords)
+}
+
+// GeoidUndulation returns the height of the geoid above the ellipsoid at lon
+// and lat, in degrees, in meters.
+func (t *VerticalTransformer) GeoidUndulation(lon, lat float64) (float64, error) {
+	coord, err := t.pj.Forward(NewCoord(lon, lat, 0, 0))
+	if err != nil {
+		return 0, err
+	}
+	return -coord.Z(), nil
+}
+
+// Destroy releases all resources associated with t.
+func (t *VerticalTransformer) Destroy() {
+	t.pj.Destroy()
+}
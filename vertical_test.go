@@ -0,0 +1,101 @@
+package proj_test
+
+import (
+	"errors"
+	"io/fs"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/michiho/go-proj/v10"
+)
+
+func TestNewVerticalTransformer(t *testing.T) {
+	defer runtime.GC()
+
+	verticalTransformer, err := proj.NewVerticalTransformer("EPSG:4979", "EPSG:4326", "EPSG:5773", nil)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skip(err)
+	}
+	assert.NoError(t, err)
+	defer verticalTransformer.Destroy()
+	assert.NotZero(t, verticalTransformer.Grids)
+
+	// The EGM96 geoid is about 17.16m above the WGS84 ellipsoid at 0°N 0°E.
+	undulation, err := verticalTransformer.GeoidUndulation(0, 0)
+	assert.NoError(t, err)
+	assertInDelta(t, 17.16, undulation, 0.05)
+
+	coords := []proj.Coord{proj.NewCoord(0, 0, 100, 0)}
+	assert.NoError(t, verticalTransformer.ToOrthometric(coords))
+	assertInDelta(t, 100-undulation, coords[0].Z(), 1e-6)
+	assert.NoError(t, verticalTransformer.ToEllipsoidal(coords))
+	assertInDelta(t, 100, coords[0].Z(), 1e-6)
+}
+
+func TestNewVerticalTransformer_missingGrid(t *testing.T) {
+	defer runtime.GC()
+
+	databasePath, err := proj.DatabasePath()
+	assert.NoError(t, err)
+
+	// Hide grids that are in the search paths or were downloaded before.
+	t.Setenv("PROJ_USER_WRITABLE_DIRECTORY", t.TempDir())
+	context := proj.NewContext()
+	_ = context.EnableNetwork(false)
+	context.SetSearchPaths([]string{t.TempDir()})
+	assert.NoError(t, context.SetDatabasePath(databasePath, nil))
+
+	_, err = context.NewVerticalTransformer("EPSG:4979", "EPSG:4326", "EPSG:5773", nil)
+	assert.IsError(t, err, fs.ErrNotExist)
+}
+
+func TestNewVerticalTransformer_geoidModel(t *testing.T) {
+	if !proj.Capabilities().GeoidModels {
+		t.Skip("geoid models not supported")
+	}
+
+	defer runtime.GC()
+
+	// NAVD88 height has a geoid model per NOAA geoid, e.g. GEOID18.
+	area := proj.NewArea(-100, 35, -99, 36)
+	verticalTransformer, err := proj.NewVerticalTransformer("EPSG:6319", "EPSG:6318", "EPSG:5703", area)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skip(err)
+	}
+	assert.NoError(t, err)
+	defer verticalTransformer.Destroy()
+	assert.NotZero(t, verticalTransformer.Grids)
+
+	geoidModels, err := proj.GeoidModelsFromDatabase("EPSG", "5703")
+	assert.NoError(t, err)
+	if verticalTransformer.GeoidModel != "" {
+		assert.True(t, slices.Contains(geoidModels, verticalTransformer.GeoidModel), verticalTransformer.GeoidModel)
+	}
+
+	// The geoid is about 25m below the ellipsoid in Oklahoma.
+	undulation, err := verticalTransformer.GeoidUndulation(-99.5, 35.5)
+	assert.NoError(t, err)
+	assertInDelta(t, -25, undulation, 5)
+
+	// Coordinates outside the grids fail rather than keeping their heights.
+	_, err = verticalTransformer.GeoidUndulation(8.5, 47.4)
+	assert.Error(t, err)
+}
+
+func TestGeoidModelsFromDatabase(t *testing.T) {
+	if !proj.Capabilities().GeoidModels {
+		t.Skip("geoid models not supported")
+	}
+
+	// NAVD88 height.
+	geoidModels, err := proj.GeoidModelsFromDatabase("EPSG", "5703")
+	assert.NoError(t, err)
+	assert.True(t, slices.Contains(geoidModels, "GEOID18"))
+
+	geoidModels, err = proj.GeoidModelsFromDatabase("EPSG", "4326")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(geoidModels))
+}